@@ -1,10 +1,11 @@
 # analyzer/app.py
-from fastapi import FastAPI
+from fastapi import FastAPI, Request
 from pydantic import BaseModel
 from typing import List, Dict, Any
 import spacy
 import re
 import os
+import logging
 from fastapi.middleware.cors import CORSMiddleware
 
 # Load model once
@@ -12,6 +13,7 @@ MODEL = os.getenv("SPACY_MODEL", "en_core_web_sm")
 nlp = spacy.load(MODEL)
 
 app = FastAPI(title="Resume Analyzer (spaCy)")
+logger = logging.getLogger("analyzer")
 
 app.add_middleware(
     CORSMiddleware,
@@ -20,6 +22,15 @@ app.add_middleware(
     allow_headers=["*"],
 )
 
+@app.middleware("http")
+async def request_id(request: Request, call_next):
+    # The backend sends X-Request-ID, the same for all retries of one analysis
+    rid = request.headers.get("x-request-id", "-")
+    request.state.request_id = rid
+    response = await call_next(request)
+    response.headers["X-Request-ID"] = rid
+    return response
+
 class AnalyzeRequest(BaseModel):
     text: str
     job_description: str = ""  # Optional job description for matching
@@ -31,7 +42,11 @@ class Entity(BaseModel):
     end: int
     score: float = 1.0
 
+# Response contract version, the Go backend rejects versions it doesn't know
+SCHEMA_VERSION = 1
+
 class AnalyzeResponse(BaseModel):
+    schema_version: int = SCHEMA_VERSION
     entities: List[Entity]
     skills: List[str]
     summary: str
@@ -299,7 +314,7 @@ def calculate_ats_score(text: str, skills: List[str], entities: List[Entity], jo
     return (ats_score, jd_match_score, matching_skills, missing_skills)
 
 @app.post("/analyze", response_model=AnalyzeResponse)
-def analyze(req: AnalyzeRequest):
+def analyze(req: AnalyzeRequest, request: Request):
     text = req.text or ""
     
     # Limit text size to prevent memory issues
@@ -336,7 +351,7 @@ def analyze(req: AnalyzeRequest):
             missing_skills=missing_skills
         )
     except Exception as e:
-        print(f"Error analyzing text: {e}")
+        logger.error("Error analyzing text (request %s): %s", request.state.request_id, e)
         # Return empty result instead of error
         return AnalyzeResponse(
             entities=[], 
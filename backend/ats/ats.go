@@ -0,0 +1,239 @@
+// Package ats scores resumes for applicant tracking system compatibility without
+// external services. It ports the heuristics of the Python analyzer (analyzer/app.py):
+// skills from a dictionary, keyword overlap with a job description and points for
+// experience, education and resume structure. There is no named entity recognition.
+package ats
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// minJobDescriptionLength is the shortest job description that is matched against
+const minJobDescriptionLength = 10
+
+// summarySentences and maxSummaryChars bound the summary
+const (
+	summarySentences = 3
+	maxSummaryChars  = 600
+)
+
+// Result is the analysis of a resume, in the response format of the Python analyzer
+type Result struct {
+	Entities       []Entity `json:"entities"`
+	Skills         []string `json:"skills"`
+	Summary        string   `json:"summary"`
+	AtsScore       int      `json:"ats_score"`      // 0-100
+	JdMatchScore   int      `json:"jd_match_score"` // 0-100, 0 without a job description
+	MatchingSkills []string `json:"matching_skills"`
+	MissingSkills  []string `json:"missing_skills"`
+}
+
+// Entity is a named entity found in the text, the Go analyzer never finds any
+type Entity struct {
+	Text  string  `json:"text"`
+	Label string  `json:"label"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Score float64 `json:"score"`
+}
+
+// Section keywords the structure score looks for
+var (
+	experienceKeywords    = []string{"experience", "work history", "employment", "worked at", "position", "role", "job"}
+	educationKeywords     = []string{"education", "degree", "university", "college", "bachelor", "master", "phd", "diploma", "graduated"}
+	certificationKeywords = []string{"certification", "certified", "certificate", "license"}
+	structureSections     = [][]string{
+		{"summary", "objective", "profile", "about"},                // summary
+		{"projects", "portfolio", "work samples"},                   // projects
+		{"skills", "technical skills", "competencies", "expertise"}, // skills
+		{"email", "phone", "linkedin", "github", "contact"},         // contact
+		{"achievements", "awards", "honors", "accomplishments"},     // achievements
+	}
+)
+
+// Analyze scores a resume, matched against jobDescription when one is given
+func Analyze(text, jobDescription string) Result {
+	lower := strings.ToLower(text)
+	skills := extractSkills(lower)
+
+	result := Result{
+		Entities:       []Entity{},
+		Skills:         skills,
+		Summary:        summarize(text),
+		MatchingSkills: []string{},
+		MissingSkills:  []string{},
+	}
+
+	hasJD := len(strings.TrimSpace(jobDescription)) >= minJobDescriptionLength
+	score := skillsPoints(len(skills), hasJD) + experiencePoints(lower, hasJD) + structurePoints(lower, hasJD)
+	if hasJD {
+		var jdPoints int
+		jdPoints, result.JdMatchScore, result.MatchingSkills, result.MissingSkills = matchJobDescription(text, jobDescription, skills)
+		score += jdPoints
+	}
+	result.AtsScore = clamp(score, 0, 100)
+	return result
+}
+
+// skillsPoints gives up to 40 points (30 with a job description) for the number of skills
+func skillsPoints(count int, hasJD bool) int {
+	maxPoints := 40.0
+	if hasJD {
+		maxPoints = 30
+	}
+	switch {
+	case count >= 10:
+		return int(maxPoints)
+	case count >= 7:
+		return int(maxPoints * 0.875)
+	case count >= 5:
+		return int(maxPoints * 0.75)
+	case count >= 3:
+		return int(maxPoints * 0.5)
+	case count >= 1:
+		return int(maxPoints * 0.25)
+	}
+	return 0
+}
+
+// experiencePoints gives up to 30 points (25 with a job description) for mentioning
+// experience, education and certifications
+func experiencePoints(lower string, hasJD bool) int {
+	maxPoints := 30.0
+	if hasJD {
+		maxPoints = 25
+	}
+	points := 0
+	if containsAny(lower, experienceKeywords) {
+		points += int(maxPoints * 0.48)
+	}
+	if containsAny(lower, educationKeywords) {
+		points += int(maxPoints * 0.48)
+	}
+	if containsAny(lower, certificationKeywords) {
+		points += int(maxPoints * 0.24)
+	}
+	return points
+}
+
+// structurePoints gives up to 30 points (15 with a job description) for the usual resume sections
+func structurePoints(lower string, hasJD bool) int {
+	maxPoints := 30.0
+	if hasJD {
+		maxPoints = 15
+	}
+	found := 0
+	for _, keywords := range structureSections {
+		if containsAny(lower, keywords) {
+			found++
+		}
+	}
+	switch {
+	case found >= 5:
+		return int(maxPoints)
+	case found >= 4:
+		return int(maxPoints * 0.833)
+	case found >= 3:
+		return int(maxPoints * 0.667)
+	case found >= 2:
+		return int(maxPoints * 0.5)
+	case found >= 1:
+		return int(maxPoints * 0.333)
+	}
+	return 0
+}
+
+// matchJobDescription compares the resume with a job description. It returns up to
+// 30 ATS points (15 for skills, 15 for keywords), the match percentage and the job
+// description's skills the resume has and misses.
+func matchJobDescription(text, jobDescription string, resumeSkills []string) (int, int, []string, []string) {
+	jdSkills := extractSkills(strings.ToLower(jobDescription))
+	has := toSet(resumeSkills)
+
+	matching, missing := []string{}, []string{}
+	for _, skill := range jdSkills {
+		if has[skill] {
+			matching = append(matching, skill)
+		} else {
+			missing = append(missing, skill)
+		}
+	}
+
+	points := 0
+	skillRatio := 0.0
+	if len(jdSkills) > 0 {
+		skillRatio = float64(len(matching)) / float64(len(jdSkills))
+		points += int(skillRatio * 15)
+	}
+
+	keywordRatio := 0.0
+	if jdKeywords := extractKeywords(jobDescription); len(jdKeywords) > 0 {
+		resumeKeywords := extractKeywords(text)
+		shared := 0
+		for keyword := range jdKeywords {
+			if resumeKeywords[keyword] {
+				shared++
+			}
+		}
+		keywordRatio = float64(shared) / float64(len(jdKeywords))
+		points += int(keywordRatio * 15)
+	}
+
+	matchPercentage := int((skillRatio + keywordRatio) / 2 * 100)
+	return min(points, 30), matchPercentage, matching, missing
+}
+
+// summarize returns the first sentences of the text, at most maxSummaryChars
+func summarize(text string) string {
+	fields := strings.Fields(text)
+	sentences, end := 0, len(fields)
+	for i, word := range fields {
+		if strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?") {
+			sentences++
+			if sentences == summarySentences {
+				end = i + 1
+				break
+			}
+		}
+	}
+	summary := strings.Join(fields[:end], " ")
+	if len(summary) <= maxSummaryChars {
+		return summary
+	}
+
+	cut := maxSummaryChars
+	for cut > 0 && !utf8.RuneStart(summary[cut]) {
+		cut--
+	}
+	summary = summary[:cut]
+	if i := strings.LastIndexByte(summary, ' '); i > 0 {
+		summary = summary[:i]
+	}
+	return summary + "..."
+}
+
+// containsAny reports whether lower contains any of the keywords, as the Python analyzer
+// does this is a plain substring match ("role" also matches "controller")
+func containsAny(lower string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func clamp(n, lo, hi int) int {
+	return max(lo, min(n, hi))
+}
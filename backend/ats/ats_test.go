@@ -0,0 +1,226 @@
+package ats
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// without drops the Python analyzer's substring-only matches from its skills: it finds
+// "r" and "c" in any word with those letters, "go" in "good" and "sql" in "postgresql",
+// the port only matches whole terms
+func without(skills []string, substringOnly ...string) []string {
+	out := []string{}
+	for _, s := range skills {
+		if !slices.Contains(substringOnly, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// The py* values are what analyzer/app.py returns for the text without a job description
+func TestAnalyzeMatchesPythonAnalyzer(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		pySkills      []string
+		pyScore       int
+		substringOnly []string
+		wantScore     int
+	}{
+		{
+			name:      "empty",
+			text:      "",
+			pySkills:  []string{},
+			pyScore:   0,
+			wantScore: 0,
+		},
+		{
+			name:          "no resume content",
+			text:          "Hello world",
+			pySkills:      []string{"r"},
+			pyScore:       10,
+			substringOnly: []string{"r"},
+			wantScore:     0,
+		},
+		{
+			name: "complete resume",
+			text: "Jane Doe\nSkills: Go, Docker, Kubernetes, PostgreSQL, Terraform, AWS, Kafka, Linux, Git, GraphQL, gRPC, Redis\n" +
+				"Experience: Backend engineer at Acme.\nEducation: BSc, University of Texas.\nAWS Certified Developer.\n" +
+				"Summary: I build systems. Email jane@x.io. Projects: resumatch. Awards: hackathon winner.",
+			pySkills:      []string{"aws", "c", "docker", "gin", "git", "go", "graphql", "grpc", "kafka", "kubernetes", "linux", "postgresql", "r", "redis", "sql", "terraform"},
+			pyScore:       100,
+			substringOnly: []string{"c", "gin", "r", "sql"},
+			wantScore:     100,
+		},
+		{
+			name:          "sections without certifications",
+			text:          "Summary\nBackend developer.\nWork history\nAcme, 2019 - 2023\nEducation\nBachelor of Science\nSkills\nPython, Django, PostgreSQL",
+			pySkills:      []string{"c", "django", "go", "postgresql", "python", "r", "sql"},
+			pyScore:       78,
+			substringOnly: []string{"c", "go", "r", "sql"},
+			wantScore:     63,
+		},
+		{
+			name:          "mobile developer",
+			text:          "Objective: Kotlin Swift Flutter developer seeking a job. Contact: phone 555. Degree in design.",
+			pySkills:      []string{"c", "flutter", "kotlin", "r", "swift"},
+			pyScore:       73,
+			substringOnly: []string{"c", "r"},
+			wantScore:     63,
+		},
+		{
+			name:          "java developer",
+			text:          "Profile. Java Spring Hibernate MySQL Jenkins Docker Angular TypeScript developer. Certified scrum master. Portfolio online. Honors.",
+			pySkills:      []string{"angular", "c", "docker", "hibernate", "java", "jenkins", "mysql", "r", "scrum", "spring", "sql", "typescript"},
+			pyScore:       81,
+			substringOnly: []string{"c", "r", "sql"},
+			wantScore:     76,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower := strings.ToLower(tt.text)
+			// Same skill count, same points: the scoring itself is the Python formula
+			points := skillsPoints(len(tt.pySkills), false) + experiencePoints(lower, false) + structurePoints(lower, false)
+			if got := clamp(points, 0, 100); got != tt.pyScore {
+				t.Errorf("score with the Python skills = %d, Python scores %d", got, tt.pyScore)
+			}
+
+			result := Analyze(tt.text, "")
+			if want := without(tt.pySkills, tt.substringOnly...); !reflect.DeepEqual(result.Skills, want) {
+				t.Errorf("skills = %q, want %q", result.Skills, want)
+			}
+			if result.AtsScore != tt.wantScore {
+				t.Errorf("ats score = %d, want %d", result.AtsScore, tt.wantScore)
+			}
+			if result.JdMatchScore != 0 || len(result.MatchingSkills) != 0 || len(result.MissingSkills) != 0 {
+				t.Errorf("job description match without a job description: %+v", result)
+			}
+		})
+	}
+}
+
+// The py* skills are what analyzer/app.py matches and misses. Its keyword half comes
+// from spaCy, the match scores are pinned to the port's own output.
+func TestAnalyzeJobDescription(t *testing.T) {
+	const resume = "Go developer. Skills: Go, Docker, PostgreSQL. Experience at Acme."
+	const jd = "We are hiring a Go engineer with Docker, Kubernetes and PostgreSQL experience."
+
+	tests := []struct {
+		name          string
+		text          string
+		jd            string
+		pyMatching    []string
+		pyMissing     []string
+		substringOnly []string
+		wantScore     int
+		wantJdMatch   int
+	}{
+		{
+			name:          "mostly matching",
+			text:          resume,
+			jd:            jd,
+			pyMatching:    []string{"c", "docker", "go", "postgresql", "r", "sql"},
+			pyMissing:     []string{"gin", "kubernetes"},
+			substringOnly: []string{"c", "gin", "r", "sql"},
+			wantScore:     50,
+			wantJdMatch:   66,
+		},
+		{
+			name:          "nothing matching",
+			text:          "Python, Django and React developer.",
+			jd:            "Senior Java developer: Spring, Hibernate, Kafka, AWS.",
+			pyMatching:    []string{"r"},
+			pyMissing:     []string{"aws", "hibernate", "java", "kafka", "spring"},
+			substringOnly: []string{"r"},
+			wantScore:     17,
+			wantJdMatch:   7,
+		},
+		{
+			name:        "empty resume",
+			text:        "",
+			jd:          jd,
+			pyMatching:  []string{},
+			pyMissing:   []string{"docker", "go", "kubernetes", "postgresql"},
+			wantScore:   0,
+			wantJdMatch: 0,
+		},
+		{
+			name:        "empty job description",
+			text:        resume,
+			jd:          "",
+			pyMatching:  []string{},
+			pyMissing:   []string{},
+			wantScore:   43,
+			wantJdMatch: 0,
+		},
+		{
+			name:        "job description under 10 characters",
+			text:        resume,
+			jd:          "  Go   ",
+			pyMatching:  []string{},
+			pyMissing:   []string{},
+			wantScore:   43,
+			wantJdMatch: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Analyze(tt.text, tt.jd)
+			if want := without(tt.pyMatching, tt.substringOnly...); !reflect.DeepEqual(result.MatchingSkills, want) {
+				t.Errorf("matching skills = %q, want %q", result.MatchingSkills, want)
+			}
+			if want := without(tt.pyMissing, tt.substringOnly...); !reflect.DeepEqual(result.MissingSkills, want) {
+				t.Errorf("missing skills = %q, want %q", result.MissingSkills, want)
+			}
+			if result.AtsScore != tt.wantScore || result.JdMatchScore != tt.wantJdMatch {
+				t.Errorf("scores = %d, %d, want %d, %d", result.AtsScore, result.JdMatchScore, tt.wantScore, tt.wantJdMatch)
+			}
+		})
+	}
+}
+
+func TestExtractSkills(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", []string{}},
+		{"case and punctuation", "GO, Docker; kubernetes.", []string{"docker", "go", "kubernetes"}},
+		{"multi word skills", "Ruby on Rails and GitHub Actions, machine learning", []string{"github", "github actions", "machine learning", "rails", "ruby", "ruby on rails"}},
+		{"symbols", "C++, C# and Node.js", []string{"c#", "c++", "node"}},
+		{"not inside words", "A good candidate", []string{}},
+		{"plain c", "C and Rust", []string{"c", "rust"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSkills(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractSkills(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"first three sentences", "One. Two! Three? Four.", "One. Two! Three?"},
+		{"whitespace collapsed", "Jane Doe\nBackend   developer.", "Jane Doe Backend developer."},
+		{"long", strings.Repeat("word ", 200), strings.TrimSpace(strings.Repeat("word ", 120)) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarize(tt.in); got != tt.want {
+				t.Errorf("summarize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
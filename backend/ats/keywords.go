@@ -0,0 +1,111 @@
+package ats
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minKeywordLength is the shortest word kept as a keyword
+const minKeywordLength = 3
+
+// wordPattern matches words, keeping tech spellings like "c++", "node.js" and "ci/cd" whole
+var wordPattern = regexp.MustCompile(`[a-z][a-z0-9+#]*(?:[./-][a-z0-9+#]+)*`)
+
+// stopWords are dropped from keywords: function words plus words every resume and
+// job posting uses, which say nothing about the match
+var stopWords = toSet(strings.Fields(`
+	a about above after again against all also am an and any are as at be because been
+	before being below between both but by can could did do does doing down during each
+	etc few for from further had has have having he her here hers herself him himself his
+	how i if in into is it its itself just me more most my myself no nor not now of off on
+	once only or other our ours ourselves out over own per same she should so some such than
+	that the their theirs them themselves then there these they this those through to too
+	under until up upon very via was we were what when where which while who whom why will
+	with within without would you your yours yourself yourselves
+	ability able across etc including like looking must new plus preferred required requirements
+	responsibilities role strong using well work working year years
+`))
+
+// extractKeywords returns the distinct keywords of a text: lowercase words of at least
+// minKeywordLength letters that aren't stop words, reduced to their singular, plus the
+// dictionary skills the text mentions
+func extractKeywords(text string) map[string]bool {
+	lower := strings.ToLower(text)
+	keywords := make(map[string]bool)
+	for _, word := range wordPattern.FindAllString(lower, -1) {
+		if utf8.RuneCountInString(word) < minKeywordLength || stopWords[word] {
+			continue
+		}
+		if isSkill(word) {
+			keywords[word] = true
+		} else {
+			keywords[singular(word)] = true
+		}
+	}
+	for _, skill := range extractSkills(lower) {
+		keywords[skill] = true
+	}
+	return keywords
+}
+
+// extractSkills returns the dictionary skills found in a lowercase text, sorted
+func extractSkills(lower string) []string {
+	found := make(map[string]bool)
+	for _, skill := range Skills() {
+		if containsTerm(lower, skill) {
+			found[skill] = true
+		}
+	}
+	return sortedKeys(found)
+}
+
+// containsTerm reports whether term appears in text on word boundaries, so "go" doesn't
+// match "good" and "c" doesn't match "c++", while "node" still matches "node.js"
+func containsTerm(text, term string) bool {
+	for start := 0; start <= len(text)-len(term); {
+		idx := strings.Index(text[start:], term)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(term)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:idx])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (idx == 0 || !isTermChar(before)) && (end == len(text) || !isTermChar(after)) {
+			return true
+		}
+		start = idx + 1
+	}
+	return false
+}
+
+func isTermChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#'
+}
+
+// singular strips regular English plural endings: "databases" -> "database", "libraries" -> "library"
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && len(word) > 3:
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// ExtractSkills returns the dictionary skills a text mentions, sorted
+func ExtractSkills(text string) []string {
+	return extractSkills(strings.ToLower(text))
+}
@@ -0,0 +1,133 @@
+package ats
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultSkills is the skills dictionary, the same list the Python analyzer uses
+var DefaultSkills = []string{
+	// Programming Languages
+	"python", "java", "c", "c++", "c#", "javascript", "typescript", "go", "golang",
+	"rust", "ruby", "swift", "kotlin", "scala", "dart", "php", "r", "perl", "objective-c",
+	"bash", "shell", "powershell", "haskell", "elixir", "lua", "matlab", "fortran",
+	// Frontend Frameworks & Libraries
+	"react", "reactjs", "nextjs", "angular", "vue", "nuxtjs", "svelte", "solidjs",
+	"jquery", "bootstrap", "tailwindcss", "chakraui", "materialui", "redux", "mobx",
+	"lit", "astro", "vite", "webpack", "parcel", "babel",
+	// Backend Frameworks
+	"node", "express", "nestjs", "fastify", "django", "flask", "fastapi", "spring",
+	"springboot", "laravel", "rails", "ruby on rails", "asp.net", "dotnet", "gin", "fiber",
+	"echo", "phoenix", "hapi", "adonisjs", "koajs",
+	// Databases
+	"sql", "postgresql", "mysql", "mariadb", "sqlite", "mongodb", "redis", "oracle",
+	"cassandra", "elasticsearch", "dynamodb", "couchdb", "neo4j", "firebase", "supabase",
+	"prisma", "typeorm", "sequelize", "hibernate", "mongoose", "realm", "influxdb",
+	// Cloud & DevOps
+	"aws", "gcp", "azure", "digitalocean", "heroku", "vercel", "netlify", "render",
+	"docker", "kubernetes", "terraform", "ansible", "jenkins", "github actions",
+	"gitlab ci", "circleci", "travisci", "argo cd", "helm", "prometheus", "grafana",
+	"nginx", "apache", "loadbalancer", "cdn", "serverless", "lambda", "cloudformation",
+	// Version Control & Collaboration
+	"git", "github", "gitlab", "bitbucket", "svn", "mercurial",
+	// Data Science & Machine Learning
+	"numpy", "pandas", "scikit-learn", "tensorflow", "pytorch", "keras", "matplotlib",
+	"seaborn", "xgboost", "lightgbm", "catboost", "opencv", "nlp", "spacy", "transformers",
+	"huggingface", "statsmodels", "jupyter", "notebook", "colab", "data visualization",
+	"mlflow", "kubeflow", "pytorch lightning", "deep learning", "computer vision",
+	"machine learning", "artificial intelligence", "reinforcement learning",
+	// Data Engineering & Big Data
+	"hadoop", "spark", "pyspark", "kafka", "airflow", "luigi", "snowflake", "bigquery",
+	"databricks", "redshift", "data lake", "data pipeline", "etl", "elt", "presto",
+	"hive", "flink", "storm",
+	// Mobile & Cross-Platform
+	"react native", "flutter", "swiftui", "android", "ios", "xcode", "kivy", "ionic",
+	"cordova", "capacitor",
+	// AI / NLP / CV
+	"openai", "langchain", "llm", "chatgpt", "gpt", "bert", "gpt-4", "t5", "transformer",
+	"yolo", "cnn", "rnn", "gans", "stable diffusion", "speech recognition", "ocr",
+	"image classification", "nlp pipeline", "text generation",
+	// Testing & QA
+	"jest", "mocha", "chai", "enzyme", "cypress", "playwright", "puppeteer", "pytest",
+	"unittest", "postman", "newman", "selenium", "robot framework",
+	// Cybersecurity & Networking
+	"penetration testing", "ethical hacking", "owasp", "burpsuite", "metasploit",
+	"firewall", "wireshark", "nmap", "ssl", "tls", "encryption", "jwt", "oauth",
+	"sso", "networking", "vpn", "zero trust", "iam",
+	// Blockchain & Web3
+	"blockchain", "ethereum", "solidity", "web3", "smart contracts", "nft", "defi",
+	"metamask", "ethersjs", "hardhat", "truffle", "ipfs", "polygon", "solana",
+	// Misc Tools / Others
+	"restapi", "graphql", "grpc", "websocket", "mqtt", "rabbitmq", "kafka", "celery",
+	"redis queue", "microservices", "monorepo", "turborepo", "api gateway",
+	"swagger", "openapi", "postman", "insomnia", "linux", "ubuntu", "windows server",
+	"macos", "bash scripting", "automation", "devops", "agile", "scrum", "jira",
+	"confluence", "figma", "adobe xd", "ui/ux", "design systems",
+	// Game Development
+	"unity", "unreal engine", "godot", "blender", "threejs", "babylonjs",
+	// Emerging Technologies
+	"genai", "rag", "autogen", "agentic ai", "ai agent", "digital twin",
+	"iot", "embedded systems", "arduino", "raspberry pi", "robotics", "edge computing",
+	// Analytics & BI
+	"tableau", "powerbi", "looker", "metabase", "superset", "google data studio",
+	// Misc Development Skills
+	"performance optimization", "scalability", "system design", "api design",
+	"distributed systems", "event-driven architecture", "observability", "logging",
+	"monitoring", "tracing",
+}
+
+var (
+	skillsOnce sync.Once
+	skills     []string
+	skillSet   map[string]bool
+)
+
+// Skills returns the skills dictionary: the newline separated keywords of SKILLS_FILE
+// when it is set and readable, DefaultSkills otherwise. Entries are lowercase.
+func Skills() []string {
+	skillsOnce.Do(func() {
+		skills = DefaultSkills
+		if path := os.Getenv("SKILLS_FILE"); path != "" {
+			if loaded, err := loadSkills(path); err != nil {
+				fmt.Printf("⚠️  Failed to load SKILLS_FILE %s, using the default skills: %v\n", path, err)
+			} else {
+				skills = loaded
+			}
+		}
+		skillSet = toSet(skills)
+	})
+	return skills
+}
+
+// isSkill reports whether word is in the skills dictionary
+func isSkill(word string) bool {
+	Skills()
+	return skillSet[word]
+}
+
+// loadSkills reads one skill per line, blank lines are skipped
+func loadSkills(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var loaded []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if skill := strings.ToLower(strings.TrimSpace(scanner.Text())); skill != "" {
+			loaded = append(loaded, skill)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(loaded) == 0 {
+		return nil, fmt.Errorf("file has no skills")
+	}
+	return loaded, nil
+}
@@ -0,0 +1,51 @@
+// Command backfill-text extracts and stores the text of processed resumes that have no
+// stored text yet or text from an older extractor version.
+//
+//	go run ./cmd/backfill-text [-force] [-limit N] [-after ID]
+package main
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/services"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	force := flag.Bool("force", false, "re-extract every resume, also those with current text")
+	limit := flag.Int("limit", 0, "check at most this many resumes, 0 for all")
+	after := flag.Uint("after", 0, "start after this resume id, to continue an interrupted run")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables from system")
+	}
+
+	config.ConnectDatabase(config.LoadConfig())
+	if err := config.DB.AutoMigrate(&models.ResumeText{}); err != nil {
+		log.Fatal("Model migration failed: ", err)
+	}
+	if err := services.InitFileStorage(); err != nil {
+		log.Fatal("File storage setup failed: ", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := services.BackfillResumeTexts(ctx, uint(*after), *limit, *force)
+	fmt.Printf("Checked %d resumes: %d extracted, %d up to date, %d failed\n",
+		result.Checked, result.Extracted, result.UpToDate, result.Failed)
+	if len(result.FailedIds) > 0 {
+		fmt.Println("Failed resumes:", result.FailedIds)
+	}
+	if err != nil {
+		log.Fatalf("Backfill stopped after resume %d: %v (continue with -after %d)", result.LastId, err, result.LastId)
+	}
+}
@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	"backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJobProviders reports the circuit breaker state and health of every job provider
+func GetJobProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"providers": services.JobProviderStatuses(),
+	})
+}
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Limits of saved job descriptions
+const (
+	maxJobDescriptionBodyBytes = 1 << 20
+	maxJobDescriptionChars     = 50000
+)
+
+// jobDescriptionInput is the body of creating or updating a saved job description
+type jobDescriptionInput struct {
+	Title     string `json:"title"`
+	Company   string `json:"company"`
+	RawText   string `json:"raw_text"`
+	SourceUrl string `json:"source_url"`
+}
+
+// validate trims the fields and returns what is wrong with them, "" when nothing
+func (in *jobDescriptionInput) validate() string {
+	in.Title = strings.TrimSpace(in.Title)
+	in.Company = strings.TrimSpace(in.Company)
+	in.RawText = strings.TrimSpace(in.RawText)
+	in.SourceUrl = strings.TrimSpace(in.SourceUrl)
+
+	switch {
+	case in.Title == "":
+		return "title is required"
+	case in.RawText == "":
+		return "raw_text is required"
+	case utf8.RuneCountInString(in.RawText) > maxJobDescriptionChars:
+		return fmt.Sprintf("raw_text is longer than %d characters", maxJobDescriptionChars)
+	}
+	if in.SourceUrl != "" {
+		u, err := url.Parse(in.SourceUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "source_url must be an http or https URL"
+		}
+	}
+	return ""
+}
+
+// apply copies the input to a job description and parses its skills
+func (in *jobDescriptionInput) apply(jd *models.JobDescription) {
+	jd.Title = in.Title
+	jd.Company = in.Company
+	jd.RawText = in.RawText
+	jd.SourceUrl = in.SourceUrl
+	services.ApplyJobDescriptionSkills(jd)
+}
+
+// GetJobDescriptions lists the saved job descriptions of the user, newest first
+func GetJobDescriptions(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	var jds []models.JobDescription
+	if err := config.DB.Where("user_id = ?", uid).Order("updated_at DESC").Find(&jds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch job descriptions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_descriptions": jds,
+	})
+}
+
+// GetJobDescription fetches a saved job description (only if it belongs to the user)
+func GetJobDescription(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	var jd models.JobDescription
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), uid).First(&jd).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job description not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_description": jd,
+	})
+}
+
+// CreateJobDescription saves a job description and parses its required and preferred skills
+func CreateJobDescription(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	var input jobDescriptionInput
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxJobDescriptionBodyBytes)
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if problem := input.validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	jd := models.JobDescription{UserId: uid}
+	input.apply(&jd)
+	if err := config.DB.Create(&jd).Error; err != nil {
+		fmt.Println("Db error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save job description"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"job_description": jd,
+	})
+}
+
+// UpdateJobDescription replaces a saved job description and parses its skills again
+func UpdateJobDescription(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	var jd models.JobDescription
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), uid).First(&jd).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job description not found"})
+		return
+	}
+
+	var input jobDescriptionInput
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxJobDescriptionBodyBytes)
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if problem := input.validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	input.apply(&jd)
+	if err := config.DB.Save(&jd).Error; err != nil {
+		fmt.Println("Db error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save job description"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_description": jd,
+	})
+}
+
+// DeleteJobDescription deletes a saved job description. Analyses made with it keep
+// their copy of the text.
+func DeleteJobDescription(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	var jd models.JobDescription
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), uid).First(&jd).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job description not found"})
+		return
+	}
+
+	// Unlink the analyses made with it, in one transaction so none is left pointing at a deleted row
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ResumeAnalysis{}).Where("job_description_id = ?", jd.Id).Update("job_description_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&jd).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete job description"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "job description deleted successfully",
+	})
+}
+
+// jobDescriptionError responds to an error of services.ResolveJobDescription
+func jobDescriptionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrJobDescriptionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrJobDescriptionConflict):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load job description"})
+	}
+}
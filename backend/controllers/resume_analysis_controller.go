@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReanalyzeBodyBytes caps the request body of AnalyzeResume, the job description is its only field
+const maxReanalyzeBodyBytes = 1 << 20
+
+// AnalyzeResume analyzes an already uploaded resume against another job description.
+// Each run is stored as its own analysis, the resume keeps its upload analysis.
+func AnalyzeResume(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	var input struct {
+		JobDescription   string `json:"job_description"`
+		JobDescriptionId uint   `json:"job_description_id"` // saved job description, in place of the text
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxReanalyzeBodyBytes)
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	jobDescription, jobDescriptionId, err := services.ResolveJobDescription(uid, input.JobDescription, input.JobDescriptionId)
+	if err != nil {
+		jobDescriptionError(c, err)
+		return
+	}
+	if strings.TrimSpace(jobDescription) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job_description or job_description_id is required"})
+		return
+	}
+
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	analysis, err := services.ReanalyzeResume(c.Request.Context(), &resume, jobDescription, jobDescriptionId)
+	if err != nil {
+		fmt.Printf("⚠️  Re-analysis of resume %d failed: %v\n", resume.Id, err)
+		var analyzerErr *services.AnalyzerError
+		switch {
+		case errors.Is(err, services.ErrResumeNotReady):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.As(err, &analyzerErr):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "analyzer unavailable, please try again later", "kind": analyzerErr.Kind})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to analyze resume"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"analysis": analysis,
+	})
+}
+
+// GetResumeAnalyses lists the analysis runs of a resume, newest first
+func GetResumeAnalyses(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	analyses, err := services.ResumeAnalyses(resume.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch analyses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"analyses": analyses,
+	})
+}
@@ -5,9 +5,13 @@ import (
 	"backend/models"
 	"backend/services"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,199 +32,225 @@ func UploadResume(c *gin.Context) {
 		return
 	}
 
+	// Cap the request body, the file plus room for the other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.ResumeMaxBytes()+1<<20)
+	if err := c.Request.ParseMultipartForm(32 << 20); err != nil {
+		uploadErr := services.AsUploadError(err)
+		c.JSON(uploadErr.Status, uploadErr)
+		return
+	}
+
 	title := c.PostForm("title")
 	jobDescription := c.PostForm("job_description") // Optional job description for better ATS matching
-	file, err := c.FormFile("resume")
+	// or the id of a saved job description in its place
+	var savedJdId uint64
+	if v := c.PostForm("job_description_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job_description_id"})
+			return
+		}
+		savedJdId = id
+	}
+	jobDescription, jobDescriptionId, err := services.ResolveJobDescription(uid, jobDescription, uint(savedJdId))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "resume file required"})
+		jobDescriptionError(c, err)
 		return
 	}
-
-	// Validate PDF file
-	if file.Header.Get("Content-Type") != "application/pdf" {
-		fmt.Printf("⚠️  Warning: Content-Type is %s, expected application/pdf\n", file.Header.Get("Content-Type"))
+	// Optional comma separated preferences used to rank job recommendations
+	jobPrefs := services.JobPreferences{
+		Locations: splitFormList(c.PostForm("preferred_locations")),
+		JobTypes:  splitFormList(c.PostForm("preferred_job_types")),
+	}
+	file, err := c.FormFile("resume")
+	if err != nil {
+		uploadErr := services.AsUploadError(err)
+		c.JSON(uploadErr.Status, uploadErr)
+		return
 	}
 
-	// save temporarily with unique name to avoid conflicts
-	tempPath := fmt.Sprintf("./temp_resume_%d_%s", time.Now().UnixNano(), file.Filename)
-	if err := c.SaveUploadedFile(file, tempPath); err != nil {
+	// Validate size, format (PDF, DOCX, TXT, Markdown) and page count, and save under a server generated temp name.
+	// The background pipeline removes it once the resume is processed.
+	tempPath, format, err := services.SaveResumeUpload(file)
+	if err != nil {
+		var uploadErr *services.UploadError
+		if errors.As(err, &uploadErr) {
+			fmt.Printf("⚠️  Rejected upload %q: %s\n", services.SanitizeFilename(file.Filename), uploadErr.Code)
+			c.JSON(uploadErr.Status, uploadErr)
+			return
+		}
 		fmt.Println("File save error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
 		return
 	}
-	defer os.Remove(tempPath) // Clean up temp file after processing
 
-	fmt.Printf("📁 Saved temp file: %s (size: %d bytes)\n", tempPath, file.Size)
+	fmt.Printf("📁 Saved temp file: %s (%s, size: %d bytes)\n", tempPath, format, file.Size)
 
-	// Extract text from PDF BEFORE uploading
-	pdfText, err := services.ExtractTextFromPdfFile(tempPath)
-	if err != nil {
-		fmt.Println("PDF Extraction Error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to extract text from PDF"})
-		return
+	if title == "" {
+		name := services.SanitizeFilename(file.Filename)
+		title = strings.TrimSuffix(name, filepath.Ext(name))
 	}
-	fmt.Println("Extracted text length:", len(pdfText))
 
-	// Analyze the extracted text with optional job description
-	analysis, err := services.AnalyzeResumeText(pdfText, jobDescription)
+	contentHash, err := services.HashFile(tempPath)
 	if err != nil {
-		fmt.Println("AI Analysis Error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze resume with AI"})
+		fmt.Println("Hash error:", err)
+		os.Remove(tempPath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
 		return
 	}
-	fmt.Println("Analysis completed")
-
-	// upload to Appwrite (new storage service)
-	url, err := services.UploadResume(tempPath)
-	if err != nil {
-		fmt.Println("Upload Error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload to Appwrite"})
-		return
+	jdHash := services.HashJobDescription(jobDescription)
+
+	// Identical bytes uploaded before: reuse that analysis, or at least its stored file and text.
+	// force=true processes the upload from scratch anyway.
+	var reuseFileId string
+	var reuseTextFrom uint
+	if c.PostForm("force") != "true" {
+		existing, source := services.FindDuplicateResume(uid, contentHash, jdHash)
+		if source != nil {
+			reuseFileId, reuseTextFrom = source.FileId, source.Id
+		}
+		if existing != nil {
+			fmt.Printf("♻️  Identical resume already uploaded as %d, reusing it\n", existing.Id)
+			os.Remove(tempPath)
+			c.JSON(http.StatusOK, gin.H{
+				"message":      "Identical resume already uploaded, reusing its analysis",
+				"resume_id":    existing.Id,
+				"status":       existing.Status,
+				"status_url":   fmt.Sprintf("/api/resume/%d/status", existing.Id),
+				"duplicate":    true,
+				"ats_warnings": services.ResumeAtsWarnings(existing),
+			})
+			return
+		}
 	}
-	fmt.Println("Uploaded to:", url)
 
-	// store in database
+	// store in database, the analysis fields are filled in by the pipeline
 	resume := models.Resume{
 		UserId:         uid,
 		Title:          title,
-		FileUrl:        url,
+		FileId:         reuseFileId,
+		FileFormat:     format,
+		ContentHash:    contentHash,
+		JdHash:         jdHash,
 		AnalysisResult: "{}",
 		AtsScore:       0,
 		JdMatchScore:   0,
 		MatchingSkills: "[]",
 		MissingSkills:  "[]",
+		Sections:       "{}",
+		Status:         models.ResumeStatusPending,
+		Stages:         services.InitialResumeStages(),
 		UploadedAt:     time.Now(),
 	}
 	if err := config.DB.Create(&resume).Error; err != nil {
 		fmt.Println("Db error:", err)
+		os.Remove(tempPath)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save resume"})
 		return
 	}
 
-	// Save full analysis JSON and extract fields to their own columns
-	resume.AnalysisResult = analysis
-	var parsed map[string]interface{}
-	parseErr := json.Unmarshal([]byte(analysis), &parsed)
-	if parseErr != nil {
-		// non-fatal: keep default values if parsing fails
-		fmt.Println("⚠️  Warning: failed to parse analysis JSON:", parseErr)
-	} else {
-		fmt.Println("✅ Successfully parsed analysis JSON")
-
-		// Extract ats_score
-		if v, ok := parsed["ats_score"]; ok && v != nil {
-			switch t := v.(type) {
-			case float64:
-				resume.AtsScore = int(t)
-			case int:
-				resume.AtsScore = t
-			}
-		}
+	// Hand the resume over to the background pipeline
+	err = services.EnqueueResume(services.ResumeTask{
+		ResumeId:         resume.Id,
+		FilePath:         tempPath,
+		TextFrom:         reuseTextFrom,
+		JobDescription:   jobDescription,
+		JobDescriptionId: jobDescriptionId,
+		JobPrefs:         jobPrefs,
+	})
+	if err != nil {
+		fmt.Println("Enqueue error:", err)
+		os.Remove(tempPath)
+		config.DB.Delete(&resume)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "too many resumes are being processed, please try again shortly"})
+		return
+	}
 
-		// Extract jd_match_score
-		if v, ok := parsed["jd_match_score"]; ok && v != nil {
-			switch t := v.(type) {
-			case float64:
-				resume.JdMatchScore = int(t)
-			case int:
-				resume.JdMatchScore = t
-			}
-		}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "Resume uploaded, processing started",
+		"resume_id":  resume.Id,
+		"status":     resume.Status,
+		"status_url": fmt.Sprintf("/api/resume/%d/status", resume.Id),
+		"duplicate":  false,
+	})
+}
 
-		// Extract matching_skills (array)
-		if v, ok := parsed["matching_skills"]; ok && v != nil {
-			if skillsBytes, err := json.Marshal(v); err == nil {
-				resume.MatchingSkills = string(skillsBytes)
-			}
-		}
+// GetResumeStatus reports the processing status and per-stage progress of a resume
+func GetResumeStatus(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
-		// Extract missing_skills (array)
-		if v, ok := parsed["missing_skills"]; ok && v != nil {
-			if skillsBytes, err := json.Marshal(v); err == nil {
-				resume.MissingSkills = string(skillsBytes)
-			}
-		}
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
 	}
 
-	if err := config.DB.Save(&resume).Error; err != nil {
-		fmt.Println("Db save error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update resume analysis"})
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
 		return
 	}
 
-	// Fetch job recommendations based on extracted skills
-	var recommendedJobs []services.Job
-	// Extract skills array from analysis (use parsed map if available)
-	var skills []string
-
-	if parseErr == nil && parsed != nil {
-		fmt.Println("🔍 Looking for skills in parsed JSON...")
-		if skillsInterface, ok := parsed["skills"]; ok && skillsInterface != nil {
-			fmt.Printf("✅ Found skills field: %v\n", skillsInterface)
-			if skillsArray, ok := skillsInterface.([]interface{}); ok {
-				for _, skill := range skillsArray {
-					if skillStr, ok := skill.(string); ok {
-						skills = append(skills, skillStr)
-					}
-				}
-				fmt.Printf("✅ Extracted %d skills: %v\n", len(skills), skills)
-			} else {
-				fmt.Println("⚠️  Skills field is not an array")
-			}
-		} else {
-			fmt.Println("⚠️  No skills field found in parsed JSON")
-		}
-	} else {
-		fmt.Println("⚠️  Parsed JSON is nil or parse error occurred")
-	}
-
-	// Fetch 5-10 jobs based on skills
-	if len(skills) > 0 {
-		fmt.Printf("🔍 Fetching job recommendations for %d skills\n", len(skills))
-		jobs, err := services.FetchJobRecommendations(skills, 8)
-		if err != nil {
-			fmt.Println("⚠️  Job fetch error (non-fatal):", err)
-			// Don't fail the entire upload if job fetch fails
-		} else {
-			recommendedJobs = jobs
-			fmt.Printf("✅ Fetched %d job recommendations\n", len(recommendedJobs))
-
-			// Save job recommendations to database
-			if len(recommendedJobs) > 0 {
-				fmt.Println("💾 Saving job recommendations to database...")
-				for _, job := range recommendedJobs {
-					jobRec := models.JobRecommendation{
-						ResumeId:    resume.Id,
-						Title:       job.Title,
-						Company:     job.Company,
-						Location:    job.Location,
-						Description: job.Description,
-						Salary:      job.Salary,
-						JobUrl:      job.JobUrl,
-						PostedDate:  job.PostedDate,
-						JobType:     job.JobType,
-					}
-					if err := config.DB.Create(&jobRec).Error; err != nil {
-						fmt.Printf("⚠️  Failed to save job recommendation: %v\n", err)
-						// Continue saving other jobs even if one fails
-					}
-				}
-				fmt.Printf("✅ Saved %d job recommendations to database\n", len(recommendedJobs))
-			}
-		}
-	} else {
-		fmt.Println("⚠️  No skills extracted, skipping job recommendations")
+	stages := json.RawMessage(resume.Stages)
+	if !json.Valid(stages) {
+		stages = json.RawMessage("[]")
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":          "Resume uploaded successfully",
-		"file_url":         url,
-		"analysis_result":  resume.AnalysisResult,
-		"ats_score":        resume.AtsScore,
-		"jd_match_score":   resume.JdMatchScore,
-		"matching_skills":  resume.MatchingSkills,
-		"missing_skills":   resume.MissingSkills,
-		"recommended_jobs": recommendedJobs,
+		"resume_id":    resume.Id,
+		"status":       resume.Status,
+		"stage":        resume.Stage,
+		"stages":       stages,
+		"error":        resume.Error,
+		"ats_warnings": services.ResumeAtsWarnings(&resume),
+	})
+}
+
+// GetResumeText returns the stored extracted text of a resume (only if it belongs to the user)
+func GetResumeText(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	text, err := services.StoredResumeText(resume.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch resume text"})
+		return
+	}
+	if text == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume text not available"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resume_id":         resume.Id,
+		"text":              text.Text,
+		"format":            text.Format,
+		"extractor_version": text.ExtractorVersion,
+		"current":           services.ResumeTextIsCurrent(text),
+		"extracted_at":      text.UpdatedAt,
 	})
 }
 
@@ -299,8 +329,10 @@ func DeleteResume(c *gin.Context) {
 		return
 	}
 
-	// Delete associated job recommendations first
+	// Delete associated job recommendations, analysis runs and extracted text first
 	config.DB.Where("resume_id = ?", resumeId).Delete(&models.JobRecommendation{})
+	config.DB.Where("resume_id = ?", resumeId).Delete(&models.ResumeAnalysis{})
+	config.DB.Where("resume_id = ?", resumeId).Delete(&models.ResumeText{})
 
 	// Delete the resume
 	if err := config.DB.Delete(&resume).Error; err != nil {
@@ -308,6 +340,12 @@ func DeleteResume(c *gin.Context) {
 		return
 	}
 
+	// Delete the stored file. If this fails the file is orphaned and the
+	// storage reconciler removes it on its next run.
+	if err := services.DeleteResumeFile(c.Request.Context(), resume.FileId); err != nil {
+		fmt.Printf("⚠️  Failed to delete file %s of resume %d: %v\n", resume.FileId, resume.Id, err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "resume deleted successfully",
 	})
@@ -339,12 +377,56 @@ func GetResumeJobs(c *gin.Context) {
 
 	// Fetch job recommendations
 	var jobs []models.JobRecommendation
-	if err := config.DB.Where("resume_id = ?", resumeId).Order("created_at DESC").Find(&jobs).Error; err != nil {
+	if err := config.DB.Where("resume_id = ?", resumeId).Order("score DESC, created_at DESC").Find(&jobs).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch job recommendations"})
 		return
 	}
 
+	// Placeholder fallback jobs are never stored, build them on the fly
+	if len(jobs) == 0 && resume.Status == models.ResumeStatusDone && services.JobFallbackMode() == services.FallbackPlaceholder {
+		if skills := services.AnalysisSkills(resume.AnalysisResult); len(skills) > 0 {
+			c.JSON(http.StatusOK, gin.H{
+				"jobs":           services.PlaceholderJobs(skills, 8),
+				"synthetic_jobs": true,
+				"fallback_mode":  services.FallbackPlaceholder,
+			})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"jobs": jobs,
+		"jobs":           jobs,
+		"synthetic_jobs": hasSyntheticJobs(jobs),
+		"fallback_mode":  storedFallbackMode(jobs),
 	})
 }
+
+// hasSyntheticJobs reports whether any job was generated by the fallback instead of a provider
+func hasSyntheticJobs(jobs []models.JobRecommendation) bool {
+	for _, job := range jobs {
+		if job.Synthetic {
+			return true
+		}
+	}
+	return false
+}
+
+// storedFallbackMode is the fallback mode that generated the stored jobs, "" for real
+// postings. Only demo jobs are ever stored.
+func storedFallbackMode(jobs []models.JobRecommendation) string {
+	if hasSyntheticJobs(jobs) {
+		return services.FallbackDemo
+	}
+	return ""
+}
+
+// splitFormList splits a comma separated form value into trimmed, non-empty values
+func splitFormList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/services"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sseHeartbeat keeps idle event streams open through proxies
+const sseHeartbeat = 15 * time.Second
+
+// StreamResumeEvents streams the processing progress of a resume as Server-Sent Events:
+// stage transitions, the analysis result, each job recommendation and a final done/failed event.
+// Clients reconnecting with Last-Event-ID only receive the events they missed.
+func StreamResumeEvents(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	streamResumeEvents(c, resume)
+}
+
+// GetResumeEventsURL returns a short-lived signed link to the event stream of a resume,
+// for EventSource clients that can't send the Authorization header
+func GetResumeEventsURL(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Select("id", "user_id").Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	url, expiresAt := services.SignedEventsURL(resume.Id, resume.UserId)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"url":        url,
+		"expires_at": expiresAt,
+	})
+}
+
+// StreamSignedResumeEvents streams the events of a resume for a signed link
+// from GetResumeEventsURL. EventSource reconnects reuse the link until it expires.
+func StreamSignedResumeEvents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	var resume models.Resume
+	if err := config.DB.First(&resume, id).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	if !services.VerifyEventsSignature(resume.Id, resume.UserId, c.Query("expires"), c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	streamResumeEvents(c, resume)
+}
+
+// streamResumeEvents replays the events after Last-Event-ID and follows the live ones
+func streamResumeEvents(c *gin.Context, resume models.Resume) {
+	lastEventId, _ := strconv.Atoi(c.GetHeader("Last-Event-ID"))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+
+	replay, live, cancel, ok := services.SubscribeResumeEvents(resume.Id, lastEventId)
+	defer cancel()
+
+	// No live log (processed before a restart, or expired): send what the database has.
+	// Jobs in the order they were saved, as they were published.
+	if !ok {
+		var jobs []models.JobRecommendation
+		config.DB.Where("resume_id = ?", resume.Id).Order("id").Find(&jobs)
+		replay = services.ResumeSnapshotEvents(resume, jobs, lastEventId)
+	}
+
+	// Tell EventSource clients how long to wait before reconnecting
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	for _, event := range replay {
+		if err := writeSSE(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	if live == nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, open := <-live:
+			if !open {
+				// processing finished, or we were too slow and should reconnect
+				return
+			}
+			if err := writeSSE(c.Writer, event); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeSSE writes one event in the text/event-stream format
+func writeSSE(w io.Writer, event services.ResumeEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+	return err
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/services"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetResumeFile streams the stored file of a resume (only if it belongs to the user).
+// With ?redirect=true it redirects to a short-lived signed download URL instead,
+// for links opened outside of the app (no Authorization header needed).
+func GetResumeFile(c *gin.Context) {
+	// Extract authenticated user ID from context
+	uidVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	uid, ok := uidVal.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	resumeId := c.Param("id")
+	var resume models.Resume
+	if err := config.DB.Where("id = ? AND user_id = ?", resumeId, uid).First(&resume).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		return
+	}
+
+	if resume.FileId == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resume file not available"})
+		return
+	}
+
+	if c.Query("redirect") == "true" {
+		url, expiresAt := services.SignedFileURL(resume.Id, resume.FileId)
+		c.Header("Cache-Control", "no-store")
+		c.Header("Expires", expiresAt.UTC().Format(http.TimeFormat))
+		c.Redirect(http.StatusFound, url)
+		return
+	}
+
+	streamResumeFile(c, resume)
+}
+
+// DownloadSignedFile streams the file of a resume for a signed download URL
+// created by GetResumeFile. The signature replaces authentication.
+func DownloadSignedFile(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+
+	var resume models.Resume
+	if err := config.DB.Select("id", "file_id", "file_format").First(&resume, id).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	if !services.VerifyFileSignature(resume.Id, resume.FileId, c.Query("expires"), c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	streamResumeFile(c, resume)
+}
+
+// streamResumeFile copies a stored resume file to the response
+func streamResumeFile(c *gin.Context, resume models.Resume) {
+	file, err := services.OpenResumeFile(c.Request.Context(), resume.FileId)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to open file %s of resume %d: %v\n", resume.FileId, resume.Id, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to load resume file"})
+		return
+	}
+	defer file.Close()
+
+	name, contentType := services.ResumeFileName(resume)
+	c.DataFromReader(http.StatusOK, -1, contentType, file, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("inline; filename=%q", name),
+		"Cache-Control":          "private, no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"backend/config"
 	"backend/models"
 	"backend/routes"
+	"backend/services"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -25,19 +27,27 @@ func main() {
 	fmt.Println("JWT_SECRET from env:", os.Getenv("JWT_SECRET"))
 
 	// auto migrate models
-	err = config.DB.AutoMigrate(&models.User{}, &models.Resume{}, &models.JobRecommendation{})
+	err = config.DB.AutoMigrate(&models.User{}, &models.Resume{}, &models.JobRecommendation{}, &models.JobFeedCache{}, &models.ResumeAnalysis{}, &models.ResumeText{}, &models.JobDescription{})
 	if err != nil {
 		log.Fatal("Model migration failed", err)
 	}
 	log.Println("Database tables migrated successfully")
 
+	if err := services.InitFileStorage(); err != nil {
+		log.Fatal("File storage setup failed: ", err)
+	}
+	services.MigrateResumeFileUrls()
+	services.ConfigureJobFeedCache(config.DB)
+	services.StartResumePipeline(context.Background())
+	services.StartStorageReconciler(context.Background())
+
 	router := gin.Default()
 
 	// CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Last-Event-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
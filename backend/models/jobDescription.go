@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// JobDescription is a job description a user saved to analyze resumes against
+type JobDescription struct {
+	Id              uint      `gorm:"primaryKey" json:"id"`
+	UserId          uint      `gorm:"index" json:"user_id"`
+	Title           string    `json:"title"`
+	Company         string    `json:"company"`
+	RawText         string    `gorm:"type:text" json:"raw_text"`
+	SourceUrl       string    `json:"source_url"`                                      // where the posting was found
+	RequiredSkills  string    `gorm:"type:jsonb;default:'[]'" json:"required_skills"`  // JSON array of strings
+	PreferredSkills string    `gorm:"type:jsonb;default:'[]'" json:"preferred_skills"` // JSON array of strings, "nice to have"
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	User            User      `gorm:"foreignKey:UserId" json:"-"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// JobFeedCache stores the last raw feed downloaded from a job board
+type JobFeedCache struct {
+	Provider  string    `gorm:"primaryKey" json:"provider"`
+	Body      []byte    `json:"-"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
@@ -3,16 +3,21 @@ package models
 import "time"
 
 type JobRecommendation struct {
-	Id          uint      `gorm:"primaryKey" json:"id"`
-	ResumeId    uint      `json:"resume_id"`
-	Title       string    `json:"title"`
-	Company     string    `json:"company"`
-	Location    string    `json:"location"`
-	Description string    `gorm:"type:text" json:"description"`
-	Salary      string    `json:"salary"`
-	JobUrl      string    `json:"job_url"`
-	PostedDate  string    `json:"posted_date"`
-	JobType     string    `json:"job_type"`
-	CreatedAt   time.Time `json:"created_at"`
-	Resume      Resume    `gorm:"foreignKey:ResumeId"`
+	Id            uint      `gorm:"primaryKey" json:"id"`
+	ResumeId      uint      `json:"resume_id"`
+	Title         string    `json:"title"`
+	Company       string    `json:"company"`
+	Location      string    `json:"location"`
+	Description   string    `gorm:"type:text" json:"description"`
+	Salary        string    `json:"salary"`
+	JobUrl        string    `json:"job_url"`
+	PostedDate    string    `json:"posted_date"`
+	JobType       string    `json:"job_type"`
+	Source        string    `gorm:"index" json:"source"`              // provider name, e.g. "remoteok"
+	SourceId      string    `gorm:"index" json:"source_id"`           // provider's own posting ID
+	Score         float64   `gorm:"default:0" json:"score"`           // relevance score (0-100)
+	MatchedSkills string    `gorm:"type:jsonb" json:"matched_skills"` // JSON array of resume skills found in the posting
+	Synthetic     bool      `gorm:"default:false" json:"synthetic"`   // demo job from the fallback, not a real posting
+	CreatedAt     time.Time `json:"created_at"`
+	Resume        Resume    `gorm:"foreignKey:ResumeId"`
 }
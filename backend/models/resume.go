@@ -3,15 +3,61 @@ package models
 import "time"
 
 type Resume struct {
-	Id             uint      `gorm:"primaryKey" json:"id"`
-	UserId         uint      `json:"user_id"`
-	Title          string    `json:"title"`
-	FileUrl        string    `json:"file_url"` // appwrite storage url
-	AnalysisResult string    `gorm:"type:jsonb" json:"analysis_result"`
-	AtsScore       int       `gorm:"default:0" json:"ats_score"`
-	JdMatchScore   int       `gorm:"default:0" json:"jd_match_score"`
-	MatchingSkills string    `gorm:"type:jsonb" json:"matching_skills"` // JSON array of strings
-	MissingSkills  string    `gorm:"type:jsonb" json:"missing_skills"`  // JSON array of strings
-	UploadedAt     time.Time `json:"uploaded_at"`
-	User           User      `gorm:"foreignKey:UserId"`
+	Id               uint      `gorm:"primaryKey" json:"id"`
+	UserId           uint      `json:"user_id"`
+	Title            string    `json:"title"`
+	FileUrl          string    `json:"file_url"`                  // protected download endpoint, /api/resume/:id/file
+	FileId           string    `gorm:"index" json:"file_id"`      // file ID in the storage backend
+	FileFormat       string    `json:"file_format"`               // pdf, docx, txt or md
+	ContentHash      string    `gorm:"index" json:"content_hash"` // SHA-256 of the uploaded file, hex
+	JdHash           string    `json:"-"`                         // SHA-256 of the job description it was analyzed against
+	AnalysisResult   string    `gorm:"type:jsonb" json:"analysis_result"`
+	AtsScore         int       `gorm:"default:0" json:"ats_score"`
+	JdMatchScore     int       `gorm:"default:0" json:"jd_match_score"`
+	MatchingSkills   string    `gorm:"type:jsonb" json:"matching_skills"`                // JSON array of strings
+	MissingSkills    string    `gorm:"type:jsonb" json:"missing_skills"`                 // JSON array of strings
+	Sections         string    `gorm:"type:jsonb;default:'{}'" json:"sections"`          // parsed sections (contact, experience, ...) as JSON
+	ExtractionReport string    `gorm:"type:jsonb;default:'{}'" json:"extraction_report"` // text extraction quality and ATS readability warnings as JSON
+	Status           string    `gorm:"default:done" json:"status"`                       // pending, processing, done, failed
+	Stage            string    `json:"stage"`                                            // current (or last) processing stage
+	Stages           string    `gorm:"type:jsonb;default:'[]'" json:"stages"`            // JSON array of ResumeStage
+	Error            string    `json:"error"`                                            // why processing failed
+	UploadedAt       time.Time `json:"uploaded_at"`
+	UpdatedAt        time.Time `gorm:"index" json:"updated_at"` // bumped by every save, processing resumes not updated for long are stuck
+	User             User      `gorm:"foreignKey:UserId"`
+}
+
+// Resume processing statuses
+const (
+	ResumeStatusPending    = "pending"
+	ResumeStatusProcessing = "processing"
+	ResumeStatusDone       = "done"
+	ResumeStatusFailed     = "failed"
+)
+
+// Resume processing stages, in the order the pipeline runs them
+const (
+	StageExtracting   = "extracting"
+	StageAnalyzing    = "analyzing"
+	StageUploading    = "uploading"
+	StageFetchingJobs = "fetching_jobs"
+	StageDone         = "done"
+)
+
+// Stage statuses
+const (
+	StageStatusPending = "pending"
+	StageStatusRunning = "running"
+	StageStatusDone    = "done"
+	StageStatusFailed  = "failed"
+	StageStatusSkipped = "skipped"
+)
+
+// ResumeStage is the progress of one processing stage, stored in Resume.Stages
+type ResumeStage struct {
+	Stage      string     `json:"stage"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
 }
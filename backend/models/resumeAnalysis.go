@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ResumeAnalysis is one analysis run of a resume against a job description.
+// The upload creates the first one, POST /api/resume/:id/analyze adds more.
+type ResumeAnalysis struct {
+	Id               uint      `gorm:"primaryKey" json:"id"`
+	ResumeId         uint      `gorm:"index" json:"resume_id"`
+	JobDescription   string    `gorm:"type:text" json:"job_description"`
+	JobDescriptionId *uint     `gorm:"index" json:"job_description_id"` // saved job description, nil for raw text or once deleted
+	JdHash           string    `json:"-"`                               // SHA-256 of the job description
+	Analyzer         string    `json:"analyzer"`                        // remote or local
+	AnalysisResult   string    `gorm:"type:jsonb" json:"analysis_result"`
+	AtsScore         int       `gorm:"default:0" json:"ats_score"`
+	JdMatchScore     int       `gorm:"default:0" json:"jd_match_score"`
+	MatchingSkills   string    `gorm:"type:jsonb" json:"matching_skills"` // JSON array of strings
+	MissingSkills    string    `gorm:"type:jsonb" json:"missing_skills"`  // JSON array of strings
+	CreatedAt        time.Time `json:"created_at"`
+	Resume           Resume    `gorm:"foreignKey:ResumeId" json:"-"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ResumeText is the text extracted from a resume file, kept so re-analysis and
+// search don't have to download and parse the file again
+type ResumeText struct {
+	Id               uint      `gorm:"primaryKey" json:"id"`
+	ResumeId         uint      `gorm:"uniqueIndex" json:"resume_id"`
+	Text             string    `gorm:"type:text" json:"text"`
+	Format           string    `json:"format"`                         // pdf, docx, txt or md
+	ExtractorVersion string    `gorm:"index" json:"extractor_version"` // services.ExtractorVersion at extraction time
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"` // last (re-)extraction
+	Resume           Resume    `gorm:"foreignKey:ResumeId" json:"-"`
+}
@@ -12,6 +12,8 @@ func SetupRoutes(router *gin.Engine) {
 	{
 		api.POST("/signup", controllers.SignUp)
 		api.POST("/login", controllers.Login)
+		api.GET("/files/:id", controllers.DownloadSignedFile)        // signed, expiring resume download links
+		api.GET("/events/:id", controllers.StreamSignedResumeEvents) // signed, expiring event streams (EventSource)
 
 		protected := api.Group("/")
 		protected.Use(middlewares.AuthMiddleware())
@@ -22,6 +24,19 @@ func SetupRoutes(router *gin.Engine) {
 			protected.GET("/resume/:id", controllers.GetResumeById)
 			protected.DELETE("/resume/:id", controllers.DeleteResume)
 			protected.GET("/resume/:id/jobs", controllers.GetResumeJobs)
+			protected.GET("/resume/:id/status", controllers.GetResumeStatus)
+			protected.GET("/resume/:id/events", controllers.StreamResumeEvents)
+			protected.GET("/resume/:id/events/url", controllers.GetResumeEventsURL)
+			protected.GET("/resume/:id/file", controllers.GetResumeFile)
+			protected.GET("/resume/:id/text", controllers.GetResumeText)
+			protected.POST("/resume/:id/analyze", controllers.AnalyzeResume)
+			protected.GET("/resume/:id/analyses", controllers.GetResumeAnalyses)
+			protected.GET("/jobs/providers", controllers.GetJobProviders)
+			protected.GET("/job-descriptions", controllers.GetJobDescriptions)
+			protected.POST("/job-descriptions", controllers.CreateJobDescription)
+			protected.GET("/job-descriptions/:id", controllers.GetJobDescription)
+			protected.PUT("/job-descriptions/:id", controllers.UpdateJobDescription)
+			protected.DELETE("/job-descriptions/:id", controllers.DeleteJobDescription)
 		}
 	}
 }
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"backend/ats"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnalysisSchemaVersion is the version of the analyzer response contract this backend
+// understands. Analyzers send it as schema_version, responses without it are version 1.
+const AnalysisSchemaVersion = 1
+
+// Analyzers that produced an AnalysisResult
+const (
+	AnalyzerSourceRemote = "remote" // Python service
+	AnalyzerSourceLocal  = "local"  // built-in Go analyzer
+)
+
+// AnalysisResult is the validated analysis of a resume, stored as JSON in Resume.AnalysisResult
+type AnalysisResult struct {
+	SchemaVersion  int              `json:"schema_version"`
+	Analyzer       string           `json:"analyzer"` // remote or local
+	Entities       []AnalysisEntity `json:"entities"`
+	Skills         []string         `json:"skills"`
+	Summary        string           `json:"summary"`
+	AtsScore       int              `json:"ats_score"`      // 0-100
+	JdMatchScore   int              `json:"jd_match_score"` // 0-100, 0 without a job description
+	MatchingSkills []string         `json:"matching_skills"`
+	MissingSkills  []string         `json:"missing_skills"`
+	// ContractWarnings lists deviations from the contract that were tolerated, e.g. unknown fields
+	ContractWarnings []string `json:"contract_warnings,omitempty"`
+}
+
+// AnalysisEntity is a named entity the analyzer found in the resume
+type AnalysisEntity struct {
+	Text  string  `json:"text"`
+	Label string  `json:"label"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Score float64 `json:"score"`
+}
+
+// AnalysisContractError is an analyzer response that doesn't match the contract
+type AnalysisContractError struct {
+	Problems []string
+}
+
+func (e *AnalysisContractError) Error() string {
+	return "analyzer response does not match the contract: " + strings.Join(e.Problems, "; ")
+}
+
+// analyzerResponse is the wire format of the contract, pointers tell missing fields from zero values
+type analyzerResponse struct {
+	SchemaVersion  *int             `json:"schema_version"`
+	Entities       []AnalysisEntity `json:"entities"`
+	Skills         *[]string        `json:"skills"`
+	Summary        *string          `json:"summary"`
+	AtsScore       *int             `json:"ats_score"`
+	JdMatchScore   *int             `json:"jd_match_score"`
+	MatchingSkills *[]string        `json:"matching_skills"`
+	MissingSkills  *[]string        `json:"missing_skills"`
+}
+
+// analyzerResponseFields are the fields of the contract, others are flagged
+var analyzerResponseFields = map[string]bool{
+	"schema_version": true, "entities": true, "skills": true, "summary": true, "ats_score": true,
+	"jd_match_score": true, "matching_skills": true, "missing_skills": true,
+}
+
+// ParseAnalyzerResponse decodes and validates a response of the Python analyzer.
+// Missing or mistyped required fields, scores outside 0-100 and newer schema versions
+// are rejected with an *AnalysisContractError; unknown fields are only flagged.
+func ParseAnalyzerResponse(data []byte) (*AnalysisResult, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, &AnalysisContractError{Problems: []string{"response is not a JSON object: " + err.Error()}}
+	}
+
+	var problems, warnings []string
+	var resp analyzerResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, name := range []string{"ats_score", "jd_match_score", "skills", "matching_skills", "missing_skills"} {
+		if value, sent := fields[name]; !sent || string(value) == "null" {
+			problems = append(problems, fmt.Sprintf("missing required field %q", name))
+		}
+	}
+	for name := range fields {
+		if !analyzerResponseFields[name] {
+			warnings = append(warnings, fmt.Sprintf("unknown field %q", name))
+		}
+	}
+	sort.Strings(warnings)
+	if len(problems) > 0 {
+		return nil, &AnalysisContractError{Problems: problems}
+	}
+
+	version := 1
+	if resp.SchemaVersion != nil {
+		version = *resp.SchemaVersion
+	}
+
+	result := &AnalysisResult{
+		SchemaVersion:    version,
+		Analyzer:         AnalyzerSourceRemote,
+		Entities:         resp.Entities,
+		Skills:           *resp.Skills,
+		AtsScore:         *resp.AtsScore,
+		JdMatchScore:     *resp.JdMatchScore,
+		MatchingSkills:   *resp.MatchingSkills,
+		MissingSkills:    *resp.MissingSkills,
+		ContractWarnings: warnings,
+	}
+	if resp.Summary != nil {
+		result.Summary = *resp.Summary
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// localAnalysisResult converts a result of the built-in analyzer
+func localAnalysisResult(r ats.Result) *AnalysisResult {
+	entities := make([]AnalysisEntity, len(r.Entities))
+	for i, e := range r.Entities {
+		entities[i] = AnalysisEntity(e)
+	}
+	return &AnalysisResult{
+		SchemaVersion:  AnalysisSchemaVersion,
+		Analyzer:       AnalyzerSourceLocal,
+		Entities:       entities,
+		Skills:         r.Skills,
+		Summary:        r.Summary,
+		AtsScore:       r.AtsScore,
+		JdMatchScore:   r.JdMatchScore,
+		MatchingSkills: r.MatchingSkills,
+		MissingSkills:  r.MissingSkills,
+	}
+}
+
+// Validate checks the values of a result: a supported schema version, scores within
+// 0-100 and no empty skill names. Nil lists are normalized to empty ones.
+func (r *AnalysisResult) Validate() error {
+	var problems []string
+	if r.SchemaVersion < 1 || r.SchemaVersion > AnalysisSchemaVersion {
+		problems = append(problems, fmt.Sprintf("unsupported schema_version %d, this backend understands up to %d",
+			r.SchemaVersion, AnalysisSchemaVersion))
+	}
+	if r.AtsScore < 0 || r.AtsScore > 100 {
+		problems = append(problems, fmt.Sprintf("ats_score %d is outside 0-100", r.AtsScore))
+	}
+	if r.JdMatchScore < 0 || r.JdMatchScore > 100 {
+		problems = append(problems, fmt.Sprintf("jd_match_score %d is outside 0-100", r.JdMatchScore))
+	}
+	for name, list := range map[string][]string{
+		"skills": r.Skills, "matching_skills": r.MatchingSkills, "missing_skills": r.MissingSkills,
+	} {
+		for _, skill := range list {
+			if strings.TrimSpace(skill) == "" {
+				problems = append(problems, fmt.Sprintf("%s contains an empty skill", name))
+				break
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return &AnalysisContractError{Problems: problems}
+	}
+
+	if r.Entities == nil {
+		r.Entities = []AnalysisEntity{}
+	}
+	if r.Skills == nil {
+		r.Skills = []string{}
+	}
+	if r.MatchingSkills == nil {
+		r.MatchingSkills = []string{}
+	}
+	if r.MissingSkills == nil {
+		r.MissingSkills = []string{}
+	}
+	return nil
+}
@@ -1,114 +1,143 @@
 package services
 
 import (
-	"bytes"
-	"encoding/json"
+	"backend/ats"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strings"
 
 	pdf "github.com/ledongthuc/pdf"
 )
 
-// ExtractTextFromPdfFile extracts clean text from a local PDF file
+// ExtractTextFromPdfFile extracts the text of a local PDF file, see ExtractResumeText
 func ExtractTextFromPdfFile(filePath string) (string, error) {
+	pages, err := extractPdfPages(filePath)
+	if err != nil {
+		return "", err
+	}
+	return joinPages(pages), nil
+}
+
+// extractPdfPages extracts the text of each page of a PDF. Pages that fail keep
+// their error, the remaining pages are still extracted.
+func extractPdfPages(filePath string) ([]ExtractedPage, error) {
 	// Open the PDF file
 	file, reader, err := pdf.Open(filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open PDF: %v", err)
+		return nil, fmt.Errorf("failed to open PDF: %v", err)
 	}
 	defer file.Close()
 
-	var textBuilder strings.Builder
 	totalPages := reader.NumPage()
+	mode := PdfExtractMode()
+	pages := make([]ExtractedPage, 0, totalPages)
 
-	fmt.Printf("📄 PDF has %d pages\n", totalPages)
+	fmt.Printf("📄 PDF has %d pages (%s extraction)\n", totalPages, mode)
 
 	// Extract text from each page
 	for pageNum := 1; pageNum <= totalPages; pageNum++ {
 		page := reader.Page(pageNum)
 		if page.V.IsNull() {
+			pages = append(pages, ExtractedPage{Err: fmt.Errorf("page object is missing")})
 			continue
 		}
-
-		text, err := page.GetPlainText(nil)
-		if err != nil {
-			fmt.Printf("⚠️  Warning: Could not extract text from page %d: %v\n", pageNum, err)
-			continue
+		extracted := ExtractedPage{HasImages: pageHasImages(page.Resources(), 0)}
+
+		if mode == PdfExtractLayout {
+			text, err := layoutPageText(page)
+			if err == nil {
+				extracted.Text = text
+				pages = append(pages, extracted)
+				continue
+			}
+			if err != errNoLayout {
+				fmt.Printf("⚠️  Warning: Layout extraction failed on page %d, using plain text: %v\n", pageNum, err)
+			}
 		}
 
-		textBuilder.WriteString(text)
-		textBuilder.WriteString("\n")
-	}
-
-	extractedText := textBuilder.String()
-
-	// Validate the extracted text
-	if len(extractedText) == 0 {
-		return "", fmt.Errorf("no text could be extracted from PDF")
+		extracted.Text, extracted.Err = plainPageText(page)
+		if extracted.Err != nil {
+			fmt.Printf("⚠️  Warning: Could not extract text from page %d: %v\n", pageNum, extracted.Err)
+		}
+		pages = append(pages, extracted)
 	}
 
-	fmt.Printf("Extracted text length: %d characters\n", len(extractedText))
-
-	// Limit text size to 50KB
-	maxChars := 50000
-	if len(extractedText) > maxChars {
-		fmt.Printf("Text too long (%d chars), truncating to %d chars\n", len(extractedText), maxChars)
-		extractedText = extractedText[:maxChars]
-	}
-	return extractedText, nil
+	return pages, nil
 }
 
-func AnalyzeResumeText(text string, jobDescription string) (string, error) {
-	// Call the local FastAPI analyzer service
-	analyzerURL := os.Getenv("ANALYZER_URL")
-	if analyzerURL == "" {
-		analyzerURL = "http://localhost:8000/analyze" // default
-	}
+// plainPageText returns the text of a page in content stream order
+func plainPageText(page pdf.Page) (text string, err error) {
+	// The content parser panics on some malformed streams
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed page content: %v", r)
+		}
+	}()
+	return page.GetPlainText(nil)
+}
 
-	fmt.Println("Calling analyzer at:", analyzerURL)
-	fmt.Println("Text length:", len(text))
-	if jobDescription != "" {
-		fmt.Println("Job description provided, length:", len(jobDescription))
+// pageHasImages reports whether page resources draw raster images, directly
+// or inside form XObjects (scanners often wrap the page image in one)
+func pageHasImages(resources pdf.Value, depth int) bool {
+	xobjects := resources.Key("XObject")
+	for _, name := range xobjects.Keys() {
+		xobject := xobjects.Key(name)
+		switch xobject.Key("Subtype").Name() {
+		case "Image":
+			return true
+		case "Form":
+			if depth < 2 && pageHasImages(xobject.Key("Resources"), depth+1) {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	requestBody := map[string]string{
-		"text":            text,
-		"job_description": jobDescription,
-	}
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
-	}
+// Analyzer modes (ANALYZER_MODE)
+const (
+	AnalyzerRemote   = "remote"   // the Python analyzer service at ANALYZER_URL
+	AnalyzerLocal    = "local"    // the built-in Go analyzer (backend/ats)
+	AnalyzerFallback = "fallback" // the Python service, the Go analyzer when the service fails
+)
 
-	req, err := http.NewRequest("POST", analyzerURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+// AnalyzerMode returns the configured analyzer mode, fallback by default
+func AnalyzerMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("ANALYZER_MODE"))); mode {
+	case AnalyzerRemote, AnalyzerLocal, AnalyzerFallback:
+		return mode
+	case "":
+	default:
+		fmt.Printf("⚠️  Invalid ANALYZER_MODE %q, using %s\n", mode, AnalyzerFallback)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return AnalyzerFallback
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Println("Analyzer connection error:", err)
-		return "", fmt.Errorf("failed to call analyzer service: %v", err)
+// AnalyzeResumeText scores the resume text against an optional job description with
+// the analyzer ANALYZER_MODE selects. Remote failures are *AnalyzerError.
+func AnalyzeResumeText(ctx context.Context, text string, jobDescription string) (*AnalysisResult, error) {
+	switch AnalyzerMode() {
+	case AnalyzerLocal:
+		return analyzeLocal(text, jobDescription)
+	case AnalyzerRemote:
+		return analyzeRemote(ctx, text, jobDescription)
 	}
-	defer resp.Body.Close()
-
-	fmt.Println("Analyzer response status:", resp.StatusCode)
 
-	respData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %v", err)
+	analysis, err := analyzeRemote(ctx, text, jobDescription)
+	if err == nil || ctx.Err() != nil {
+		return analysis, err
 	}
+	fmt.Printf("⚠️  Analyzer service unavailable, using the built-in analyzer: %v\n", err)
+	return analyzeLocal(text, jobDescription)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Println("Analyzer response body:", string(respData))
-		return "", fmt.Errorf("analyzer service returned status: %d", resp.StatusCode)
+// analyzeLocal scores the resume with the built-in Go analyzer
+func analyzeLocal(text string, jobDescription string) (*AnalysisResult, error) {
+	result := localAnalysisResult(ats.Analyze(text, jobDescription))
+	if err := result.Validate(); err != nil {
+		return nil, err
 	}
-
-	fmt.Println("Analyzer response:", string(respData))
-	return string(respData), nil
+	fmt.Printf("✅ Built-in analyzer: ATS score %d, %d skills\n", result.AtsScore, len(result.Skills))
+	return result, nil
 }
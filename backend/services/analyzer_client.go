@@ -0,0 +1,250 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Analyzer client defaults, overridable with ANALYZER_TIMEOUT, ANALYZER_RETRIES and ANALYZER_BACKOFF
+const (
+	defaultAnalyzerURL     = "http://localhost:8000/analyze"
+	defaultAnalyzerTimeout = 30 * time.Second // per attempt
+	defaultAnalyzerRetries = 2                // attempts after the first one
+	defaultAnalyzerBackoff = 500 * time.Millisecond
+	maxAnalyzerBackoff     = 10 * time.Second
+	maxAnalyzerResponse    = 10 << 20
+)
+
+// Analyzer failure kinds, AnalyzerError.Kind
+const (
+	AnalyzerErrTimeout           = "timeout"            // no response within ANALYZER_TIMEOUT
+	AnalyzerErrConnectionRefused = "connection_refused" // nothing listens at ANALYZER_URL
+	AnalyzerErrConnection        = "connection"         // DNS, reset and other network errors
+	AnalyzerErrBadStatus         = "bad_status"         // non-200 response
+	AnalyzerErrBadResponse       = "bad_response"       // 200 with a body that breaks the contract
+	AnalyzerErrCanceled          = "canceled"           // the caller gave up, e.g. on shutdown
+)
+
+// analyzerHTTPClient has no timeout of its own, every attempt gets a context deadline
+var analyzerHTTPClient = &http.Client{}
+
+// AnalyzerError is a failed call to the analyzer service, after all retries
+type AnalyzerError struct {
+	Kind       string
+	RequestId  string // sent as X-Request-ID, also in the analyzer's logs
+	Attempts   int
+	StatusCode int // for bad_status
+	Err        error
+}
+
+func (e *AnalyzerError) Error() string {
+	var what string
+	switch e.Kind {
+	case AnalyzerErrTimeout:
+		what = fmt.Sprintf("analyzer timed out after %s", analyzerTimeout())
+	case AnalyzerErrConnectionRefused:
+		what = "analyzer refused the connection, is it running?"
+	case AnalyzerErrConnection:
+		what = "could not connect to the analyzer"
+	case AnalyzerErrBadStatus:
+		what = fmt.Sprintf("analyzer returned status %d", e.StatusCode)
+	case AnalyzerErrBadResponse:
+		what = "analyzer returned a bad response"
+	case AnalyzerErrCanceled:
+		what = "analyzer call was canceled"
+	default:
+		what = "analyzer call failed"
+	}
+	return fmt.Sprintf("%s (request %s, %d %s): %v", what, e.RequestId, e.Attempts, plural(e.Attempts, "attempt", "attempts"), e.Err)
+}
+
+func (e *AnalyzerError) Unwrap() error {
+	return e.Err
+}
+
+// retryable reports whether another attempt may succeed
+func (e *AnalyzerError) retryable() bool {
+	switch e.Kind {
+	case AnalyzerErrTimeout, AnalyzerErrConnectionRefused, AnalyzerErrConnection:
+		return true
+	case AnalyzerErrBadStatus:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	}
+	return false
+}
+
+// analyzerURL returns ANALYZER_URL or the local default
+func analyzerURL() string {
+	if url := os.Getenv("ANALYZER_URL"); url != "" {
+		return url
+	}
+	return defaultAnalyzerURL
+}
+
+// analyzerTimeout is the deadline of one attempt (ANALYZER_TIMEOUT, default 30s)
+func analyzerTimeout() time.Duration {
+	if timeout := envDuration(defaultAnalyzerTimeout, "ANALYZER_TIMEOUT"); timeout > 0 {
+		return timeout
+	}
+	return defaultAnalyzerTimeout
+}
+
+// analyzerRetries is how often a failed call is retried (ANALYZER_RETRIES, default 2, 0 disables)
+func analyzerRetries() int {
+	if v := os.Getenv("ANALYZER_RETRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+		fmt.Printf("⚠️  Invalid ANALYZER_RETRIES %q, using %d\n", v, defaultAnalyzerRetries)
+	}
+	return defaultAnalyzerRetries
+}
+
+// analyzeRemote calls the Python analyzer service. Analysis has no side effects, so
+// timeouts, connection errors, 429 and 5xx are retried with exponential backoff and
+// jitter. All attempts carry the same X-Request-ID.
+func analyzeRemote(ctx context.Context, text string, jobDescription string) (*AnalysisResult, error) {
+	url := analyzerURL()
+	requestId := newRequestId()
+	retries := analyzerRetries()
+	backoff := envDuration(defaultAnalyzerBackoff, "ANALYZER_BACKOFF")
+
+	fmt.Printf("Calling analyzer at %s (request %s, text length %d)\n", url, requestId, len(text))
+	if jobDescription != "" {
+		fmt.Println("Job description provided, length:", len(jobDescription))
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"text":            text,
+		"job_description": jobDescription,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, retryAfter, err := callAnalyzer(ctx, url, requestId, body)
+		if err == nil {
+			return result, nil
+		}
+		err.Attempts = attempt
+		if !err.retryable() || attempt > retries || ctx.Err() != nil {
+			fmt.Println("⚠️ ", err)
+			return nil, err
+		}
+
+		delay := max(backoffDelay(backoff, attempt), retryAfter)
+		fmt.Printf("⚠️  Analyzer attempt %d failed (%s), retrying in %s: %v\n", attempt, err.Kind, delay.Round(time.Millisecond), err.Err)
+		select {
+		case <-ctx.Done():
+			return nil, &AnalyzerError{Kind: AnalyzerErrCanceled, RequestId: requestId, Attempts: attempt, Err: ctx.Err()}
+		case <-time.After(delay):
+		}
+	}
+}
+
+// callAnalyzer makes one attempt. retryAfter is the delay a 429 or 503 asked for.
+func callAnalyzer(ctx context.Context, url, requestId string, body []byte) (*AnalysisResult, time.Duration, *AnalyzerError) {
+	fail := func(kind string, err error) *AnalyzerError {
+		return &AnalyzerError{Kind: kind, RequestId: requestId, Err: err}
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, analyzerTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fail(AnalyzerErrConnection, fmt.Errorf("failed to create request: %v", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", requestId)
+
+	resp, err := analyzerHTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, fail(classifyAnalyzerError(ctx, err), err)
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(io.LimitReader(resp.Body, maxAnalyzerResponse))
+	if err != nil {
+		return nil, 0, fail(classifyAnalyzerError(ctx, err), fmt.Errorf("failed to read response: %v", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Analyzer response status %d (request %s): %s\n", resp.StatusCode, requestId, TruncateUTF8(string(respData), 500))
+		badStatus := fail(AnalyzerErrBadStatus, fmt.Errorf("%s", http.StatusText(resp.StatusCode)))
+		badStatus.StatusCode = resp.StatusCode
+		return nil, retryAfterDelay(resp.Header.Get("Retry-After")), badStatus
+	}
+
+	result, err := ParseAnalyzerResponse(respData)
+	if err != nil {
+		return nil, 0, fail(AnalyzerErrBadResponse, err)
+	}
+	for _, warning := range result.ContractWarnings {
+		fmt.Printf("⚠️  Analyzer response (request %s): %s\n", requestId, warning)
+	}
+	return result, 0, nil
+}
+
+// classifyAnalyzerError tells timeouts, refused connections and other network errors apart
+func classifyAnalyzerError(ctx context.Context, err error) string {
+	var netErr net.Error
+	switch {
+	case ctx.Err() != nil:
+		return AnalyzerErrCanceled // the caller's context, not the attempt deadline
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return AnalyzerErrTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return AnalyzerErrConnectionRefused
+	}
+	return AnalyzerErrConnection
+}
+
+// backoffDelay returns the wait before retry number attempt: base doubled per attempt,
+// capped, with the upper half randomized so retries of concurrent uploads spread out
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if delay > maxAnalyzerBackoff || delay < 0 {
+		delay = maxAnalyzerBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + mathrand.N(half+1)
+}
+
+// retryAfterDelay parses a Retry-After header given in seconds, capped at maxAnalyzerBackoff
+func retryAfterDelay(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	if delay := time.Duration(seconds) * time.Second; delay < maxAnalyzerBackoff {
+		return delay
+	}
+	return maxAnalyzerBackoff
+}
+
+// newRequestId returns a random ID that ties backend and analyzer logs together
+func newRequestId() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const validAnalyzerResponse = `{"schema_version":1,"ats_score":80,"jd_match_score":70,"skills":["go"],"matching_skills":["go"],"missing_skills":[]}`
+
+// analyzerStep is how the fake analyzer answers one attempt, status 0 hangs until the client gives up
+type analyzerStep struct {
+	status int
+	body   string
+}
+
+// fakeAnalyzer answers attempts with steps in order, repeating the last one.
+// The returned func lists the X-Request-ID of every request so far.
+func fakeAnalyzer(t *testing.T, steps ...analyzerStep) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var requestIds []string
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestIds = append(requestIds, r.Header.Get("X-Request-ID"))
+		step := steps[min(len(requestIds), len(steps))-1]
+		mu.Unlock()
+
+		if step.status == 0 {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.WriteHeader(step.status)
+		w.Write([]byte(step.body))
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) }) // runs first, hanging handlers would block Close
+
+	t.Setenv("ANALYZER_URL", srv.URL)
+	t.Setenv("ANALYZER_RETRIES", "2")
+	t.Setenv("ANALYZER_BACKOFF", "1ms")
+	t.Setenv("ANALYZER_TIMEOUT", "100ms")
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requestIds...)
+	}
+}
+
+func TestAnalyzeRemoteRetries(t *testing.T) {
+	ok := analyzerStep{http.StatusOK, validAnalyzerResponse}
+	hang := analyzerStep{}
+
+	tests := []struct {
+		name         string
+		steps        []analyzerStep
+		wantAttempts int
+		wantKind     string // "" expects success
+		wantStatus   int
+	}{
+		{"500 then success", []analyzerStep{{http.StatusInternalServerError, "boom"}, ok}, 2, "", 0},
+		{"502 and 503 then success", []analyzerStep{{http.StatusBadGateway, ""}, {http.StatusServiceUnavailable, ""}, ok}, 3, "", 0},
+		{"429 then success", []analyzerStep{{http.StatusTooManyRequests, ""}, ok}, 2, "", 0},
+		{"timeout then success", []analyzerStep{hang, ok}, 2, "", 0},
+		{"5xx until retries run out", []analyzerStep{{http.StatusServiceUnavailable, ""}}, 3, AnalyzerErrBadStatus, http.StatusServiceUnavailable},
+		{"timeouts until retries run out", []analyzerStep{hang}, 3, AnalyzerErrTimeout, 0},
+		{"400 not retried", []analyzerStep{{http.StatusBadRequest, `{"detail":"text missing"}`}, ok}, 1, AnalyzerErrBadStatus, http.StatusBadRequest},
+		{"422 not retried", []analyzerStep{{http.StatusUnprocessableEntity, ""}, ok}, 1, AnalyzerErrBadStatus, http.StatusUnprocessableEntity},
+		{"score out of range not retried", []analyzerStep{{http.StatusOK, `{"ats_score":150,"jd_match_score":70,"skills":[],"matching_skills":[],"missing_skills":[]}`}, ok}, 1, AnalyzerErrBadResponse, 0},
+		{"missing fields not retried", []analyzerStep{{http.StatusOK, `{"ats_score":80}`}, ok}, 1, AnalyzerErrBadResponse, 0},
+		{"invalid json not retried", []analyzerStep{{http.StatusOK, `<html>`}, ok}, 1, AnalyzerErrBadResponse, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := fakeAnalyzer(t, tt.steps...)
+
+			result, err := analyzeRemote(context.Background(), "Jane Doe, Go developer", "")
+			requestIds := received()
+
+			if got := len(requestIds); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			for i, id := range requestIds {
+				if id == "" || id != requestIds[0] {
+					t.Errorf("attempt %d sent request ID %q, want %q on every attempt", i+1, id, requestIds[0])
+				}
+			}
+
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want success", err)
+				}
+				if result.AtsScore != 80 {
+					t.Errorf("ats score = %d, want 80", result.AtsScore)
+				}
+				return
+			}
+
+			var analyzerErr *AnalyzerError
+			if !errors.As(err, &analyzerErr) {
+				t.Fatalf("err = %v, want an *AnalyzerError", err)
+			}
+			if analyzerErr.Kind != tt.wantKind || analyzerErr.StatusCode != tt.wantStatus {
+				t.Errorf("error is %s %d, want %s %d", analyzerErr.Kind, analyzerErr.StatusCode, tt.wantKind, tt.wantStatus)
+			}
+			if analyzerErr.Attempts != tt.wantAttempts {
+				t.Errorf("error reports %d attempts, want %d", analyzerErr.Attempts, tt.wantAttempts)
+			}
+			if len(requestIds) > 0 && analyzerErr.RequestId != requestIds[0] {
+				t.Errorf("error request ID = %q, the analyzer saw %q", analyzerErr.RequestId, requestIds[0])
+			}
+		})
+	}
+}
+
+// Nothing listening: retried, then reported as a refused connection
+func TestAnalyzeRemoteConnectionRefused(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	t.Setenv("ANALYZER_URL", url)
+	t.Setenv("ANALYZER_RETRIES", "2")
+	t.Setenv("ANALYZER_BACKOFF", "1ms")
+
+	_, err := analyzeRemote(context.Background(), "Jane Doe", "")
+	var analyzerErr *AnalyzerError
+	if !errors.As(err, &analyzerErr) {
+		t.Fatalf("err = %v, want an *AnalyzerError", err)
+	}
+	if analyzerErr.Kind != AnalyzerErrConnectionRefused || analyzerErr.Attempts != 3 {
+		t.Errorf("error is %s after %d attempts, want %s after 3", analyzerErr.Kind, analyzerErr.Attempts, AnalyzerErrConnectionRefused)
+	}
+}
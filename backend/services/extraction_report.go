@@ -0,0 +1,166 @@
+package services
+
+import (
+	"backend/models"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Extraction thresholds
+const (
+	imageOnlyMaxChars     = 30   // a page with images and fewer characters is treated as image-only
+	nonPrintableWarnShare = 0.05 // share of non-printable characters that gets flagged
+	littleTextChars       = 300  // fewer characters in the whole resume get flagged
+)
+
+// ATS readability warning codes, ExtractionWarning.Code
+const (
+	WarnImageOnlyPages   = "image_only_pages"
+	WarnUnreadablePages  = "unreadable_pages"
+	WarnPagesWithoutText = "pages_without_text"
+	WarnNonPrintableText = "non_printable_text"
+	WarnLittleText       = "little_text"
+	WarnTextTruncated    = "text_truncated"
+)
+
+// ExtractionReport describes how much of a resume file could be read as text.
+// Pages are numbered from 1, formats without pages count as one page.
+type ExtractionReport struct {
+	Format            string              `json:"format"`
+	PagesTotal        int                 `json:"pages_total"`
+	PagesExtracted    int                 `json:"pages_extracted"` // pages that gave text
+	CharsPerPage      []int               `json:"chars_per_page"`  // non-space characters
+	NonPrintableShare float64             `json:"non_printable_share"`
+	ImageOnlyPages    []int               `json:"image_only_pages"`
+	FailedPages       []int               `json:"failed_pages"`
+	Truncated         bool                `json:"truncated"`
+	Warnings          []ExtractionWarning `json:"warnings"`
+}
+
+// ExtractionWarning is one ATS readability problem found while extracting a resume
+type ExtractionWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Pages   []int  `json:"pages,omitempty"`
+}
+
+// NewExtractionReport measures the extracted pages of a resume and lists the problems
+// an applicant tracking system would have reading it
+func NewExtractionReport(format string, pages []ExtractedPage) *ExtractionReport {
+	report := &ExtractionReport{
+		Format:         format,
+		PagesTotal:     len(pages),
+		CharsPerPage:   make([]int, len(pages)),
+		ImageOnlyPages: []int{},
+		FailedPages:    []int{},
+		Warnings:       []ExtractionWarning{},
+	}
+
+	var emptyPages []int
+	total, nonPrintable := 0, 0
+	for i, page := range pages {
+		chars, bad := countChars(page.Text)
+		report.CharsPerPage[i] = chars
+		total += chars
+		nonPrintable += bad
+
+		switch {
+		case page.Err != nil:
+			report.FailedPages = append(report.FailedPages, i+1)
+		case page.HasImages && chars < imageOnlyMaxChars:
+			report.ImageOnlyPages = append(report.ImageOnlyPages, i+1)
+		case chars == 0:
+			emptyPages = append(emptyPages, i+1)
+		}
+		if chars > 0 && page.Err == nil {
+			report.PagesExtracted++
+		}
+	}
+	if total > 0 {
+		report.NonPrintableShare = math.Round(float64(nonPrintable)/float64(total)*1000) / 1000
+	}
+
+	if n := len(report.ImageOnlyPages); n > 0 {
+		report.warn(WarnImageOnlyPages, report.ImageOnlyPages,
+			"%s %s only images, ATS systems can't read scanned or image text. Export the resume as a text PDF from your editor instead of scanning or printing it to an image.",
+			capitalize(formatPages(report.ImageOnlyPages)), plural(n, "contains", "contain"))
+	}
+	if len(report.FailedPages) > 0 {
+		report.warn(WarnUnreadablePages, report.FailedPages,
+			"The text of %s could not be read, ATS systems will likely miss it too. Re-export the PDF, damaged or unusual files lose pages.",
+			formatPages(report.FailedPages))
+	}
+	if len(emptyPages) > 0 && len(emptyPages) < len(pages) {
+		report.warn(WarnPagesWithoutText, emptyPages,
+			"%s %s no readable text. If %s not blank, the text may have been converted to outlines or shapes, which ATS systems can't read.",
+			capitalize(formatPages(emptyPages)), plural(len(emptyPages), "has", "have"), plural(len(emptyPages), "it is", "they are"))
+	}
+	if report.NonPrintableShare >= nonPrintableWarnShare {
+		report.warn(WarnNonPrintableText, nil,
+			"%.0f%% of the extracted characters are unreadable symbols. The resume likely uses fonts without a text mapping, so ATS systems see garbled text. Use a standard font such as Arial, Calibri or Times New Roman.",
+			report.NonPrintableShare*100)
+	}
+	if total > 0 && total < littleTextChars && len(report.ImageOnlyPages) == 0 {
+		report.warn(WarnLittleText, nil,
+			"Only %d characters of text could be read. ATS systems rank resumes on their text, make sure the content isn't in images, text boxes or shapes.", total)
+	}
+	return report
+}
+
+// markTruncated records that the text was cut to maxExtractedChars
+func (r *ExtractionReport) markTruncated() {
+	r.Truncated = true
+	r.warn(WarnTextTruncated, nil,
+		"The resume text is longer than %d characters, only the beginning was analyzed. A resume of one or two pages works best with ATS systems.", maxExtractedChars)
+}
+
+func (r *ExtractionReport) warn(code string, pages []int, format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, ExtractionWarning{Code: code, Message: fmt.Sprintf(format, args...), Pages: pages})
+}
+
+// ResumeAtsWarnings returns the readability warnings stored with a resume
+func ResumeAtsWarnings(resume *models.Resume) []ExtractionWarning {
+	var report ExtractionReport
+	if err := json.Unmarshal([]byte(resume.ExtractionReport), &report); err != nil || report.Warnings == nil {
+		return []ExtractionWarning{}
+	}
+	return report.Warnings
+}
+
+// countChars counts the non-space characters of text and how many of them aren't printable:
+// control characters, replacement characters and private use code points, which is
+// what glyphs of fonts without a Unicode mapping come out as
+func countChars(text string) (chars, nonPrintable int) {
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		chars++
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			nonPrintable++
+		}
+	}
+	return chars, nonPrintable
+}
+
+// formatPages formats page numbers for messages, "page 2" or "pages 2, 3"
+func formatPages(pages []int) string {
+	numbers := make([]string, len(pages))
+	for i, page := range pages {
+		numbers[i] = strconv.Itoa(page)
+	}
+	return plural(len(pages), "page ", "pages ") + strings.Join(numbers, ", ")
+}
+
+// plural returns one for a count of 1 and other otherwise
+func plural(n int, one, other string) string {
+	if n == 1 {
+		return one
+	}
+	return other
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Resume file formats, also used as the file extension
+const (
+	FormatPDF      = "pdf"
+	FormatDOCX     = "docx"
+	FormatText     = "txt"
+	FormatMarkdown = "md"
+)
+
+// maxExtractedChars caps the text handed to the analyzer, in bytes
+const maxExtractedChars = 50000
+
+// extractorRevision is bumped whenever a change to extraction changes its output,
+// stored texts of older revisions are re-extracted by the backfill
+const extractorRevision = 1
+
+// ExtractorVersion identifies the extractor that produces text of a format,
+// PDF text also depends on PDF_EXTRACT_MODE: "1" for DOCX, "1-layout" for PDF
+func ExtractorVersion(format string) string {
+	if format == FormatPDF {
+		return fmt.Sprintf("%d-%s", extractorRevision, PdfExtractMode())
+	}
+	return fmt.Sprint(extractorRevision)
+}
+
+// maxDocxPartBytes caps how much of one decompressed DOCX part is read (zip bomb guard)
+const maxDocxPartBytes = 20 << 20
+
+// TextExtractor extracts the text of one resume file format, page by page.
+// Formats without pages are returned as one page.
+type TextExtractor interface {
+	Extract(filePath string) ([]ExtractedPage, error)
+}
+
+// ExtractedPage is the text of one page, Err is set when the page could not be read
+type ExtractedPage struct {
+	Text      string
+	Err       error
+	HasImages bool // the page draws raster images
+}
+
+// textExtractors maps each supported format to its extractor
+var textExtractors = map[string]TextExtractor{
+	FormatPDF:      pdfExtractor{},
+	FormatDOCX:     docxExtractor{},
+	FormatText:     plainTextExtractor{},
+	FormatMarkdown: plainTextExtractor{},
+}
+
+// ExtractResumeText sniffs the format of a resume file, extracts its text with the
+// matching extractor and caps its length. fileName is the original name of the file,
+// which tells Markdown from plain text (see DetectResumeFormat). It returns the text and
+// a report on how readable the file was, the report is also returned when no text could be extracted.
+func ExtractResumeText(filePath, fileName string) (string, *ExtractionReport, error) {
+	format, err := DetectResumeFormat(filePath, fileName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pages, err := textExtractors[format].Extract(filePath)
+	if err != nil {
+		return "", nil, err
+	}
+	text := joinPages(pages)
+	report := NewExtractionReport(format, pages)
+
+	// Validate the extracted text
+	if strings.TrimSpace(text) == "" {
+		return "", report, fmt.Errorf("no text could be extracted from the %s file", strings.ToUpper(format))
+	}
+
+	fmt.Printf("Extracted text length: %d characters (%s)\n", len(text), format)
+
+	// Limit text size to 50KB, on a rune boundary
+	if len(text) > maxExtractedChars {
+		fmt.Printf("Text too long (%d chars), truncating to %d chars\n", len(text), maxExtractedChars)
+		text = TruncateUTF8(text, maxExtractedChars)
+		report.markTruncated()
+	}
+	return text, report, nil
+}
+
+// joinPages joins the text of the pages, one line break after each page
+func joinPages(pages []ExtractedPage) string {
+	var text strings.Builder
+	for _, page := range pages {
+		if page.Text != "" {
+			text.WriteString(page.Text)
+			text.WriteString("\n")
+		}
+	}
+	return text.String()
+}
+
+// DetectResumeFormat sniffs the format of a file from its content. The file name
+// is only used to tell Markdown from plain text, which look the same.
+// Unsupported content is an *UploadError.
+func DetectResumeFormat(filePath, fileName string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	head := make([]byte, pdfHeaderWindow)
+	n, _ := io.ReadFull(f, head)
+	f.Close()
+	head = head[:n]
+
+	switch {
+	case bytes.Contains(head, []byte("%PDF-")):
+		return FormatPDF, nil
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		if isDocx(filePath) {
+			return FormatDOCX, nil
+		}
+	case looksLikeText(head):
+		lower := strings.ToLower(fileName)
+		if strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown") {
+			return FormatMarkdown, nil
+		}
+		return FormatText, nil
+	}
+	return "", uploadError(http.StatusUnsupportedMediaType, UploadErrUnsupportedFormat, "resume must be a PDF, DOCX, TXT or Markdown file")
+}
+
+// isDocx reports whether a zip file is a Word document
+func isDocx(filePath string) bool {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeText reports whether the start of a file is UTF-8 text
+func looksLikeText(head []byte) bool {
+	if len(head) == 0 || bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	// the window may end in the middle of a rune
+	for i := 0; i < utf8.UTFMax && !utf8.Valid(head); i++ {
+		head = head[:len(head)-1]
+	}
+	return utf8.Valid(head)
+}
+
+// pdfExtractor extracts text with ledongthuc/pdf
+type pdfExtractor struct{}
+
+func (pdfExtractor) Extract(filePath string) ([]ExtractedPage, error) {
+	return extractPdfPages(filePath)
+}
+
+// plainTextExtractor reads TXT and Markdown files as they are
+type plainTextExtractor struct{}
+
+func (plainTextExtractor) Extract(filePath string) ([]ExtractedPage, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	return []ExtractedPage{{Text: strings.ToValidUTF8(string(data), "")}}, nil
+}
+
+// docxExtractor reads the paragraphs of a Word document from its OOXML parts:
+// headers first (they often hold the contact details), then the body
+type docxExtractor struct{}
+
+func (docxExtractor) Extract(filePath string) ([]ExtractedPage, error) {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX: %v", err)
+	}
+	defer r.Close()
+
+	var headers []*zip.File
+	var document *zip.File
+	hasMedia := false
+	for _, f := range r.File {
+		switch {
+		case f.Name == "word/document.xml":
+			document = f
+		case strings.HasPrefix(f.Name, "word/header") && strings.HasSuffix(f.Name, ".xml"):
+			headers = append(headers, f)
+		case strings.HasPrefix(f.Name, "word/media/"):
+			hasMedia = true
+		}
+	}
+	if document == nil {
+		return nil, fmt.Errorf("DOCX has no word/document.xml")
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+
+	var text strings.Builder
+	for _, f := range append(headers, document) {
+		if err := docxPartText(f, &text); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", f.Name, err)
+		}
+	}
+	return []ExtractedPage{{Text: text.String(), HasImages: hasMedia}}, nil
+}
+
+// docxPartText appends the text of one WordprocessingML part: text runs (w:t),
+// tabs, line breaks and one line per paragraph
+func docxPartText(f *zip.File, text *strings.Builder) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxDocxPartBytes))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			// Alternate content repeats the preferred choice for older readers
+			if t.Name.Local == "Fallback" && strings.Contains(t.Name.Space, "markup-compatibility") {
+				if err := decoder.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if !isWordML(t.Name) {
+				continue
+			}
+			switch t.Name.Local {
+			case "pPr", "rPr":
+				// formatting only, its w:tabs are tab stops, not tabs
+				if err := decoder.Skip(); err != nil {
+					return err
+				}
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if !isWordML(t.Name) {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+// isWordML reports whether an element is in the WordprocessingML main namespace
+func isWordML(name xml.Name) bool {
+	return strings.HasSuffix(name.Space, "/wordprocessingml/2006/main")
+}
+
+// TruncateUTF8 cuts s to at most maxBytes bytes without splitting a multi-byte rune
+func TruncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"backend/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const markdownResume = "# Jane Doe\n\n## Skills\n\n- Go\n- PostgreSQL\n"
+
+// writeUpload saves content under a server generated name without extension, like uploads
+func writeUpload(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "upload-123")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtractResumeTextMarkdown(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     string
+	}{
+		{"resume.md", FormatMarkdown},
+		{"Resume.MARKDOWN", FormatMarkdown},
+		{"resume.txt", FormatText},
+		{"", FormatText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			text, report, err := ExtractResumeText(writeUpload(t, markdownResume), tt.fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Format != tt.want {
+				t.Errorf("format = %q, want %q", report.Format, tt.want)
+			}
+			if !strings.Contains(text, "PostgreSQL") {
+				t.Errorf("text = %q", text)
+			}
+		})
+	}
+}
+
+// The pipeline extracts from a temp file, the stored format of the upload keeps it Markdown
+func TestExtractResumeMarkdownUpload(t *testing.T) {
+	resume := models.Resume{Id: 9, FileFormat: FormatMarkdown, Stages: InitialResumeStages()}
+	dryRunDB(t, resume)
+
+	run := newResumeRun(&resume)
+	if _, err := extractResume(run, writeUpload(t, markdownResume)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(resume.ExtractionReport, `"format":"md"`) {
+		t.Errorf("extraction report = %s, want format md", resume.ExtractionReport)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How long signed links work by default, FILE_URL_TTL and EVENTS_URL_TTL
+const (
+	defaultFileURLTTL   = 5 * time.Minute
+	defaultEventsURLTTL = 5 * time.Minute
+)
+
+// ErrNoLinkSecret is returned by InitFileStorage when signed links would be signed with an empty key
+var ErrNoLinkSecret = errors.New("FILE_URL_SECRET or JWT_SECRET must be set to sign file links")
+
+// ResumeFilePath is the protected endpoint serving the file of a resume, stored as Resume.FileUrl
+func ResumeFilePath(resumeId uint) string {
+	return fmt.Sprintf("/api/resume/%d/file", resumeId)
+}
+
+// SignedFileURL returns a download link for the file of a resume that works without
+// the Authorization header until it expires (FILE_URL_TTL, default 5m)
+func SignedFileURL(resumeId uint, fileId string) (string, time.Time) {
+	expires := time.Now().Add(envDuration(defaultFileURLTTL, "FILE_URL_TTL")).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+
+	params := url.Values{}
+	params.Set("expires", exp)
+	params.Set("sig", fileSignature(resumeId, fileId, exp))
+	return fmt.Sprintf("/api/files/%d?%s", resumeId, params.Encode()), expires
+}
+
+// VerifyFileSignature checks a signed download link. The signature covers the file ID,
+// so links stop working once the file of the resume is deleted or replaced.
+func VerifyFileSignature(resumeId uint, fileId, expires, sig string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp || fileId == "" || linkSecret() == "" {
+		return false
+	}
+	expected := fileSignature(resumeId, fileId, expires)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// SignedEventsURL returns a link to the event stream of a resume for clients that can't send
+// the Authorization header (EventSource). It only has to be valid when the stream is opened
+// or reopened, for EVENTS_URL_TTL (default 5m).
+func SignedEventsURL(resumeId, userId uint) (string, time.Time) {
+	expires := time.Now().Add(envDuration(defaultEventsURLTTL, "EVENTS_URL_TTL")).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+
+	params := url.Values{}
+	params.Set("expires", exp)
+	params.Set("sig", eventsSignature(resumeId, userId, exp))
+	return fmt.Sprintf("/api/events/%d?%s", resumeId, params.Encode()), expires
+}
+
+// VerifyEventsSignature checks a signed event stream link of a resume owned by userId
+func VerifyEventsSignature(resumeId, userId uint, expires, sig string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp || linkSecret() == "" {
+		return false
+	}
+	expected := eventsSignature(resumeId, userId, expires)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// fileSignature signs resumeId, fileId and the expiry
+func fileSignature(resumeId uint, fileId, expires string) string {
+	return linkSignature(fmt.Sprintf("%d:%s:%s", resumeId, fileId, expires))
+}
+
+// eventsSignature signs resumeId, the owner and the expiry. The prefix keeps
+// event links and download links from being swapped.
+func eventsSignature(resumeId, userId uint, expires string) string {
+	return linkSignature(fmt.Sprintf("events:%d:%d:%s", resumeId, userId, expires))
+}
+
+// linkSignature signs a link with linkSecret
+func linkSignature(payload string) string {
+	mac := hmac.New(sha256.New, []byte(linkSecret()))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// linkSecret is the key of signed links, FILE_URL_SECRET (falls back to JWT_SECRET).
+// Links are never accepted without one, InitFileStorage refuses to start.
+func linkSecret() string {
+	if secret := os.Getenv("FILE_URL_SECRET"); secret != "" {
+		return secret
+	}
+	return os.Getenv("JWT_SECRET")
+}
+
+// MigrateResumeFileUrls replaces the public storage links of resumes uploaded before
+// files became private with the protected endpoint, recovering missing file IDs from the links
+func MigrateResumeFileUrls() {
+	var resumes []models.Resume
+	err := config.DB.Select("id", "file_id", "file_url").
+		Where("file_url <> '' AND file_url NOT LIKE '/api/%'").
+		Find(&resumes).Error
+	if err != nil {
+		fmt.Println("⚠️  Failed to load resumes with public file links:", err)
+		return
+	}
+
+	for _, resume := range resumes {
+		fileId := resume.FileId
+		if fileId == "" {
+			fileId = fileIdFromURL(resume.FileUrl)
+		}
+		updates := map[string]interface{}{"file_url": ResumeFilePath(resume.Id), "file_id": fileId}
+		if err := config.DB.Model(&models.Resume{}).Where("id = ?", resume.Id).Updates(updates).Error; err != nil {
+			fmt.Printf("⚠️  Failed to migrate file link of resume %d: %v\n", resume.Id, err)
+		}
+	}
+	if len(resumes) > 0 {
+		fmt.Printf("🔒 Replaced %d public file links with /api/resume/:id/file\n", len(resumes))
+	}
+}
+
+// fileIdFromURL extracts the file ID from a public storage link: the segment after
+// "files" for Appwrite (.../files/{id}/view) and local storage (.../files/{id}),
+// otherwise the last segment (S3 .../{bucket}/{id})
+func fileIdFromURL(fileUrl string) string {
+	segments := urlSegments(fileUrl)
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "files" {
+			return segments[i+1]
+		}
+	}
+	if len(segments) > 0 {
+		return segments[len(segments)-1]
+	}
+	return ""
+}
+
+// urlSegments returns the path segments of a URL
+func urlSegments(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil || rawURL == "" {
+		return nil
+	}
+	var segments []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// ResumeFileName returns the download file name and content type of a resume's file.
+// Resumes uploaded before other formats were accepted have no format, they are PDFs.
+func ResumeFileName(resume models.Resume) (string, string) {
+	format := resume.FileFormat
+	if format == "" {
+		format = FormatPDF
+	}
+	name := fmt.Sprintf("resume-%d.%s", resume.Id, format)
+	return name, contentTypeFor(name)
+}
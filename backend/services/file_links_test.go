@@ -0,0 +1,87 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSignedEventsURL(t *testing.T) {
+	t.Setenv("FILE_URL_SECRET", "test-secret")
+
+	link, _ := SignedEventsURL(42, 7)
+	if !strings.HasPrefix(link, "/api/events/42?") {
+		t.Fatalf("link = %q", link)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, sig := u.Query().Get("expires"), u.Query().Get("sig")
+
+	tests := []struct {
+		name     string
+		resumeId uint
+		userId   uint
+		expires  string
+		sig      string
+		want     bool
+	}{
+		{"valid", 42, 7, expires, sig, true},
+		{"other resume", 43, 7, expires, sig, false},
+		{"other owner", 42, 8, expires, sig, false},
+		{"changed expiry", 42, 7, expires + "0", sig, false},
+		{"expired", 42, 7, "1", eventsSignature(42, 7, "1"), false},
+		{"file link signature", 42, 7, expires, fileSignature(42, "7", expires), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyEventsSignature(tt.resumeId, tt.userId, tt.expires, tt.sig); got != tt.want {
+				t.Errorf("VerifyEventsSignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Without a secret links would be signed with an empty key, anyone could forge them
+func TestInitFileStorageRequiresLinkSecret(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", "local")
+	t.Setenv("STORAGE_LOCAL_DIR", t.TempDir())
+	previous := fileStorage
+	t.Cleanup(func() { fileStorage = previous })
+
+	tests := []struct {
+		name       string
+		fileSecret string
+		jwtSecret  string
+		wantErr    error
+	}{
+		{"no secret", "", "", ErrNoLinkSecret},
+		{"file url secret", "file-secret", "", nil},
+		{"jwt secret fallback", "", "jwt-secret", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FILE_URL_SECRET", tt.fileSecret)
+			t.Setenv("JWT_SECRET", tt.jwtSecret)
+			if err := InitFileStorage(); err != tt.wantErr {
+				t.Errorf("InitFileStorage() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyFileSignatureWithoutSecret(t *testing.T) {
+	t.Setenv("FILE_URL_SECRET", "")
+	t.Setenv("JWT_SECRET", "")
+
+	// a link signed with the empty key
+	link, _ := SignedFileURL(42, "file-1")
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if VerifyFileSignature(42, "file-1", u.Query().Get("expires"), u.Query().Get("sig")) {
+		t.Error("link signed with an empty key was accepted")
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Defaults for the job feed cache, overridable with JOB_FEED_CACHE_TTL and JOB_FEED_CACHE_STALE
+const (
+	defaultFeedCacheTTL   = 10 * time.Minute
+	defaultFeedCacheStale = time.Hour
+	feedRefreshTimeout    = 15 * time.Second
+)
+
+// feedEntry is a cached raw feed response
+type feedEntry struct {
+	Body      []byte
+	FetchedAt time.Time
+}
+
+// feedCacheStore persists feed entries by provider name
+type feedCacheStore interface {
+	Get(provider string) (feedEntry, bool)
+	Set(provider string, entry feedEntry)
+}
+
+// memoryFeedStore keeps feed entries in process memory
+type memoryFeedStore struct {
+	mu      sync.RWMutex
+	entries map[string]feedEntry
+}
+
+func newMemoryFeedStore() *memoryFeedStore {
+	return &memoryFeedStore{entries: make(map[string]feedEntry)}
+}
+
+func (s *memoryFeedStore) Get(provider string) (feedEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[provider]
+	return entry, ok
+}
+
+func (s *memoryFeedStore) Set(provider string, entry feedEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[provider] = entry
+}
+
+// postgresFeedStore keeps feed entries in the job_feed_caches table so they survive
+// restarts and are shared between instances. Memory is used as a first level while
+// its copy is fresh, after that another instance may have refreshed the table.
+type postgresFeedStore struct {
+	db     *gorm.DB
+	memory *memoryFeedStore
+}
+
+func (s *postgresFeedStore) Get(provider string) (feedEntry, bool) {
+	cached, inMemory := s.memory.Get(provider)
+	ttl, _ := feedCacheTTL(provider)
+	if inMemory && time.Since(cached.FetchedAt) < ttl {
+		return cached, true
+	}
+
+	var row models.JobFeedCache
+	if err := s.db.Where("provider = ?", provider).First(&row).Error; err != nil {
+		// the stale memory copy beats nothing when the database is unavailable
+		return cached, inMemory
+	}
+	if inMemory && !row.FetchedAt.After(cached.FetchedAt) {
+		return cached, true
+	}
+
+	entry := feedEntry{Body: row.Body, FetchedAt: row.FetchedAt}
+	s.memory.Set(provider, entry)
+	return entry, true
+}
+
+func (s *postgresFeedStore) Set(provider string, entry feedEntry) {
+	s.memory.Set(provider, entry)
+
+	row := models.JobFeedCache{Provider: provider, Body: entry.Body, FetchedAt: entry.FetchedAt}
+	if err := s.db.Save(&row).Error; err != nil {
+		fmt.Printf("⚠️  Failed to persist %s feed cache: %v\n", provider, err)
+	}
+}
+
+// feedCache serves provider feeds with a TTL and stale-while-revalidate
+type feedCache struct {
+	store feedCacheStore
+
+	mu       sync.Mutex
+	inflight map[string]*feedCall
+}
+
+// feedCall is a feed download shared by every caller waiting on the same provider
+type feedCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// jobFeedCache is nil when caching is turned off
+var jobFeedCache = &feedCache{store: newMemoryFeedStore(), inflight: make(map[string]*feedCall)}
+
+// ConfigureJobFeedCache selects the feed cache backend from JOB_FEED_CACHE:
+// "memory" (default), "postgres" (needs db) or "off"
+func ConfigureJobFeedCache(db *gorm.DB) {
+	mode := strings.ToLower(os.Getenv("JOB_FEED_CACHE"))
+	switch mode {
+	case "off", "none", "false":
+		jobFeedCache = nil
+		fmt.Println("🗄️  Job feed cache disabled")
+	case "postgres", "db":
+		if db == nil {
+			fmt.Println("⚠️  JOB_FEED_CACHE=postgres but no database, using memory cache")
+			return
+		}
+		jobFeedCache = &feedCache{
+			store:    &postgresFeedStore{db: db, memory: newMemoryFeedStore()},
+			inflight: make(map[string]*feedCall),
+		}
+		fmt.Println("🗄️  Job feed cache: postgres")
+	default:
+		fmt.Println("🗄️  Job feed cache: memory")
+	}
+}
+
+// cachedFeed returns the raw feed of a provider, calling download only when needed:
+//   - fresh entry (younger than the TTL): served from cache
+//   - stale entry (within the stale window): served from cache and refreshed in the background
+//   - missing or expired entry: downloaded now, concurrent callers share one download
+//
+// If a download fails and any cached copy exists, the cached copy is served.
+func cachedFeed(ctx context.Context, provider string, download func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	cache := jobFeedCache
+	if cache == nil {
+		return download(ctx)
+	}
+
+	ttl, stale := feedCacheTTL(provider)
+	entry, ok := cache.store.Get(provider)
+	age := time.Since(entry.FetchedAt)
+
+	if ok && age < ttl {
+		fmt.Printf("  🗄️  %s feed from cache (age %s)\n", provider, age.Round(time.Second))
+		return entry.Body, nil
+	}
+
+	if ok && age < ttl+stale {
+		fmt.Printf("  🗄️  %s feed is stale (age %s), revalidating in background\n", provider, age.Round(time.Second))
+		go func() {
+			if _, err := cache.refresh(context.Background(), provider, download); err != nil {
+				fmt.Printf("  ⚠️  %s background refresh failed: %v\n", provider, err)
+			}
+		}()
+		return entry.Body, nil
+	}
+
+	body, err := cache.refresh(ctx, provider, download)
+	if err != nil && ok {
+		fmt.Printf("  ⚠️  %s download failed, serving expired cache: %v\n", provider, err)
+		return entry.Body, nil
+	}
+	return body, err
+}
+
+// refresh downloads a feed and stores it, sharing the download with concurrent callers.
+// The download runs on its own context (feedRefreshTimeout), so a caller that gives up
+// doesn't cancel it for the others. Each caller only waits as long as its ctx allows.
+func (c *feedCache) refresh(ctx context.Context, provider string, download func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	call, ok := c.inflight[provider]
+	if !ok {
+		call = &feedCall{done: make(chan struct{})}
+		c.inflight[provider] = call
+		go c.download(call, provider, download)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// download runs a shared feed download and stores the feed
+func (c *feedCache) download(call *feedCall, provider string, download func(ctx context.Context) ([]byte, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), feedRefreshTimeout)
+	defer cancel()
+
+	call.body, call.err = download(ctx)
+	if call.err == nil {
+		c.store.Set(provider, feedEntry{Body: call.body, FetchedAt: time.Now()})
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, provider)
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// feedCacheTTL returns the TTL and stale window for a provider.
+// JOB_FEED_CACHE_TTL_<PROVIDER> overrides JOB_FEED_CACHE_TTL, same for JOB_FEED_CACHE_STALE.
+func feedCacheTTL(provider string) (time.Duration, time.Duration) {
+	suffix := "_" + strings.ToUpper(provider)
+	ttl := envDuration(defaultFeedCacheTTL, "JOB_FEED_CACHE_TTL"+suffix, "JOB_FEED_CACHE_TTL")
+	stale := envDuration(defaultFeedCacheStale, "JOB_FEED_CACHE_STALE"+suffix, "JOB_FEED_CACHE_STALE")
+	return ttl, stale
+}
+
+// envDuration returns the first valid duration among the given environment variables
+func envDuration(fallback time.Duration, keys ...string) time.Duration {
+	for _, key := range keys {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+		fmt.Printf("⚠️  Invalid %s %q, ignoring\n", key, v)
+	}
+	return fallback
+}
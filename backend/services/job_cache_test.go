@@ -0,0 +1,124 @@
+package services
+
+import (
+	"backend/models"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// A caller giving up doesn't cancel the download the other callers are waiting for
+func TestFeedCacheRefreshOutlivesFirstCaller(t *testing.T) {
+	cache := &feedCache{store: newMemoryFeedStore(), inflight: make(map[string]*feedCall)}
+
+	release := make(chan struct{})
+	var downloads atomic.Int32
+	download := func(ctx context.Context) ([]byte, error) {
+		downloads.Add(1)
+		select {
+		case <-release:
+			return []byte("feed"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := cache.refresh(first, "testfeed", download)
+		firstErr <- err
+	}()
+	for downloads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-firstErr; err != context.Canceled {
+		t.Fatalf("first caller err = %v, want %v", err, context.Canceled)
+	}
+
+	// the second caller joins the download still running
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	body, err := cache.refresh(context.Background(), "testfeed", download)
+	if err != nil || string(body) != "feed" {
+		t.Errorf("second caller got %q, %v, want the shared download", body, err)
+	}
+	if n := downloads.Load(); n != 1 {
+		t.Errorf("downloads = %d, want 1", n)
+	}
+	if entry, ok := cache.store.Get("testfeed"); !ok || string(entry.Body) != "feed" {
+		t.Errorf("feed was not stored: %+v", entry)
+	}
+}
+
+// feedStoreDB is a database that never connects, loading a feed cache row returns row
+func feedStoreDB(t *testing.T, row models.JobFeedCache) (*gorm.DB, *int) {
+	t.Helper()
+	db := fakeDB(t)
+
+	queries := 0
+	db.Callback().Query().After("gorm:query").Register("test:load_feed", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*models.JobFeedCache); ok {
+			queries++
+			*dest = row
+			tx.RowsAffected = 1
+		}
+	})
+	return db, &queries
+}
+
+// Another instance refreshed the table: a stale memory copy is not served
+func TestPostgresFeedStoreReadsThroughWhenStale(t *testing.T) {
+	t.Setenv("JOB_FEED_CACHE_TTL", "10m")
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		memory      feedEntry
+		row         models.JobFeedCache
+		wantBody    string
+		wantQueries int
+	}{
+		{
+			name:        "fresh memory copy",
+			memory:      feedEntry{Body: []byte("memory"), FetchedAt: now.Add(-time.Minute)},
+			row:         models.JobFeedCache{Provider: "testfeed", Body: []byte("db"), FetchedAt: now},
+			wantBody:    "memory",
+			wantQueries: 0,
+		},
+		{
+			name:        "stale memory copy, newer row",
+			memory:      feedEntry{Body: []byte("memory"), FetchedAt: now.Add(-time.Hour)},
+			row:         models.JobFeedCache{Provider: "testfeed", Body: []byte("db"), FetchedAt: now.Add(-time.Minute)},
+			wantBody:    "db",
+			wantQueries: 1,
+		},
+		{
+			name:        "stale memory copy, older row",
+			memory:      feedEntry{Body: []byte("memory"), FetchedAt: now.Add(-time.Hour)},
+			row:         models.JobFeedCache{Provider: "testfeed", Body: []byte("db"), FetchedAt: now.Add(-2 * time.Hour)},
+			wantBody:    "memory",
+			wantQueries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, queries := feedStoreDB(t, tt.row)
+			store := &postgresFeedStore{db: db, memory: newMemoryFeedStore()}
+			store.memory.Set("testfeed", tt.memory)
+
+			entry, ok := store.Get("testfeed")
+			if !ok || string(entry.Body) != tt.wantBody {
+				t.Errorf("Get = %q, %v, want %q", entry.Body, ok, tt.wantBody)
+			}
+			if *queries != tt.wantQueries {
+				t.Errorf("queries = %d, want %d", *queries, tt.wantQueries)
+			}
+		})
+	}
+}
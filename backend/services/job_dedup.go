@@ -0,0 +1,284 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// titleSimilarityThreshold is the minimum token overlap (Dice coefficient) for two
+// titles at the same company to be treated as the same posting
+const titleSimilarityThreshold = 0.8
+
+// titleAbbreviations expands common abbreviations so "Sr. Dev" and "Senior Developer" match
+var titleAbbreviations = map[string]string{
+	"sr":    "senior",
+	"snr":   "senior",
+	"jr":    "junior",
+	"jnr":   "junior",
+	"dev":   "developer",
+	"devs":  "developer",
+	"eng":   "engineer",
+	"engr":  "engineer",
+	"mgr":   "manager",
+	"mngr":  "manager",
+	"swe":   "software engineer",
+	"sde":   "software engineer",
+	"assoc": "associate",
+	"ml":    "machine learning",
+	"ai":    "artificial intelligence",
+	"qa":    "quality assurance",
+	"ui":    "user interface",
+	"ux":    "user experience",
+}
+
+// seniorityTokens give the level of a title. Titles at different levels are different
+// openings even at the same company ("Senior Backend Developer" vs "Backend Developer").
+var seniorityTokens = map[string]bool{
+	"intern": true, "trainee": true, "junior": true, "senior": true, "lead": true,
+	"staff": true, "principal": true, "head": true, "i": true, "ii": true, "iii": true, "iv": true,
+}
+
+// titleNoiseTokens are dropped from titles, they differ between boards for the same job
+var titleNoiseTokens = map[string]bool{
+	"m": true, "w": true, "f": true, "d": true, "x": true, "mwd": true, "fmd": true,
+	"remote": true, "hybrid": true, "onsite": true, "all": true, "genders": true,
+}
+
+// companySuffixes are legal-form tokens dropped from company names so "Acme Inc" matches "Acme"
+var companySuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "plc": true,
+	"gmbh": true, "ag": true, "se": true, "sa": true, "bv": true, "nv": true,
+	"pty": true, "srl": true, "oy": true, "ab": true, "the": true,
+}
+
+// trackingParams are query parameters removed when comparing job URLs
+var trackingParams = map[string]bool{
+	"ref": true, "referrer": true, "source": true, "src": true, "gclid": true,
+	"fbclid": true, "mc_cid": true, "mc_eid": true, "trk": true, "trackingid": true,
+	"campaign": true, "medium": true, "utm": true,
+}
+
+var (
+	parenthesesPattern = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]`)
+	nonAlnumPattern    = regexp.MustCompile(`[^a-z0-9+#]+`)
+)
+
+// deduplicateJobs merges near-duplicate postings (possibly from different providers)
+// into one job that keeps the richest fields of each copy. Order of first appearance is kept.
+func deduplicateJobs(jobs []Job) []Job {
+	unique := make([]Job, 0, len(jobs))
+	keys := make([]jobKey, 0, len(jobs))
+
+	for _, job := range jobs {
+		key := newJobKey(job)
+
+		merged := false
+		for i := range unique {
+			if keys[i].matches(key) {
+				unique[i] = mergeJobs(unique[i], job)
+				keys[i] = newJobKey(unique[i])
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			unique = append(unique, job)
+			keys = append(keys, key)
+		}
+	}
+
+	fmt.Printf("🔧 Deduplication: %d jobs → %d unique jobs\n", len(jobs), len(unique))
+	return unique
+}
+
+// jobKey holds the normalized fields used to detect duplicates
+type jobKey struct {
+	sourceId    string
+	url         string
+	company     string
+	titleTokens []string
+	seniority   string
+}
+
+func newJobKey(job Job) jobKey {
+	key := jobKey{
+		url:         canonicalJobURL(job.JobUrl),
+		company:     normalizeCompany(job.Company),
+		titleTokens: titleTokens(job.Title),
+	}
+	key.seniority = titleSeniority(key.titleTokens)
+	if job.SourceId != "" {
+		key.sourceId = strings.ToLower(job.Source + "#" + job.SourceId)
+	}
+	return key
+}
+
+// matches reports whether two keys describe the same posting
+func (k jobKey) matches(other jobKey) bool {
+	if k.sourceId != "" && k.sourceId == other.sourceId {
+		return true
+	}
+	if k.url != "" && k.url == other.url {
+		return true
+	}
+	if k.company == "" || k.company != other.company || k.seniority != other.seniority {
+		return false
+	}
+	return diceCoefficient(k.titleTokens, other.titleTokens) >= titleSimilarityThreshold
+}
+
+// mergeJobs combines two copies of the same posting, keeping the first job's
+// identity (title, source, URL) and filling in richer fields from the second
+func mergeJobs(primary, other Job) Job {
+	if len(other.Description) > len(primary.Description) {
+		primary.Description = other.Description
+	}
+	if primary.Salary == "" {
+		primary.Salary = other.Salary
+	}
+	if primary.Location == "" || primary.Location == "Not specified" {
+		primary.Location = other.Location
+	}
+	if primary.PostedDate == "" {
+		primary.PostedDate = other.PostedDate
+	}
+	if primary.JobType == "" {
+		primary.JobType = other.JobType
+	}
+	if primary.JobUrl == "" {
+		primary.JobUrl = other.JobUrl
+	}
+	if primary.Company == "" {
+		primary.Company = other.Company
+	}
+	if primary.SourceId == "" && primary.Source == other.Source {
+		primary.SourceId = other.SourceId
+	}
+	primary.Tags = mergeTags(primary.Tags, other.Tags)
+	return primary
+}
+
+// mergeTags returns the union of two tag lists, ignoring case
+func mergeTags(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, t := range append(append([]string{}, a...), b...) {
+		if k := strings.ToLower(t); !seen[k] {
+			seen[k] = true
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// titleTokens normalizes a job title into comparable tokens:
+// lowercase, no bracketed notes like "(m/w/d)", abbreviations expanded, noise removed
+func titleTokens(title string) []string {
+	title = strings.ToLower(title)
+	title = parenthesesPattern.ReplaceAllString(title, " ")
+	title = strings.NewReplacer(
+		"front-end", "frontend", "front end", "frontend",
+		"back-end", "backend", "back end", "backend",
+		"full-stack", "fullstack", "full stack", "fullstack",
+		"m/w/d", " ", "f/m/d", " ", "m/f/d", " ", "w/m/d", " ",
+	).Replace(title)
+
+	var tokens []string
+	for _, t := range strings.Fields(nonAlnumPattern.ReplaceAllString(title, " ")) {
+		if expanded, ok := titleAbbreviations[t]; ok {
+			tokens = append(tokens, strings.Fields(expanded)...)
+			continue
+		}
+		if !titleNoiseTokens[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// titleSeniority returns the sorted level tokens of a title, "" when it names no level
+func titleSeniority(tokens []string) string {
+	var levels []string
+	for _, t := range tokens {
+		if seniorityTokens[t] {
+			levels = append(levels, t)
+		}
+	}
+	sort.Strings(levels)
+	return strings.Join(levels, " ")
+}
+
+// normalizeCompany lowercases a company name and strips punctuation and legal suffixes
+func normalizeCompany(company string) string {
+	var tokens []string
+	for _, t := range strings.Fields(nonAlnumPattern.ReplaceAllString(strings.ToLower(company), " ")) {
+		if !companySuffixes[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// canonicalJobURL reduces a URL to host + path + meaningful query params so the same
+// posting linked with different schemes, "www." or tracking params compares equal
+func canonicalJobURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.TrimRight(u.EscapedPath(), "/")
+
+	query := u.Query()
+	var params []string
+	for k, values := range query {
+		lk := strings.ToLower(k)
+		if trackingParams[lk] || strings.HasPrefix(lk, "utm_") {
+			continue
+		}
+		for _, v := range values {
+			params = append(params, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+	sort.Strings(params)
+
+	canonical := host + path
+	if len(params) > 0 {
+		canonical += "?" + strings.Join(params, "&")
+	}
+	return canonical
+}
+
+// diceCoefficient measures token overlap between two token lists (0 = disjoint, 1 = same set)
+func diceCoefficient(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	setA := make(map[string]bool, len(a))
+	for _, t := range a {
+		setA[t] = true
+	}
+	setB := make(map[string]bool, len(b))
+	for _, t := range b {
+		setB[t] = true
+	}
+
+	shared := 0
+	for t := range setA {
+		if setB[t] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(setA)+len(setB))
+}
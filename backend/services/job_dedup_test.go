@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDeduplicateJobsMatching(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Job
+		wantMerge bool
+	}{
+		{
+			name:      "abbreviated seniority",
+			a:         Job{Title: "Sr. Backend Dev", Company: "Acme"},
+			b:         Job{Title: "Senior Backend Developer", Company: "Acme"},
+			wantMerge: true,
+		},
+		{
+			name:      "legal suffix",
+			a:         Job{Title: "Backend Developer", Company: "Acme Inc."},
+			b:         Job{Title: "Backend Developer", Company: "ACME"},
+			wantMerge: true,
+		},
+		{
+			name:      "gender note and hyphenation",
+			a:         Job{Title: "Back-End Engineer (m/w/d)", Company: "Acme GmbH"},
+			b:         Job{Title: "Backend Engineer", Company: "Acme"},
+			wantMerge: true,
+		},
+		{
+			name:      "same source id",
+			a:         Job{Title: "Go Developer", Company: "Acme", Source: "Adzuna", SourceId: "123"},
+			b:         Job{Title: "Golang Engineer", Company: "Other", Source: "adzuna", SourceId: "123"},
+			wantMerge: true,
+		},
+		{
+			name:      "same url with tracking params",
+			a:         Job{Title: "Go Developer", Company: "Acme", JobUrl: "https://www.jobs.example.com/view/42/?utm_source=x&ref=feed"},
+			b:         Job{Title: "Platform Engineer", Company: "Acme Labs", JobUrl: "http://jobs.example.com/view/42"},
+			wantMerge: true,
+		},
+		{
+			name:      "senior and unqualified",
+			a:         Job{Title: "Senior Backend Developer", Company: "Acme"},
+			b:         Job{Title: "Backend Developer", Company: "Acme"},
+			wantMerge: false,
+		},
+		{
+			name:      "senior and junior",
+			a:         Job{Title: "Sr. Backend Developer", Company: "Acme"},
+			b:         Job{Title: "Jr. Backend Developer", Company: "Acme"},
+			wantMerge: false,
+		},
+		{
+			name:      "level numbers",
+			a:         Job{Title: "Software Engineer II", Company: "Acme"},
+			b:         Job{Title: "Software Engineer III", Company: "Acme"},
+			wantMerge: false,
+		},
+		{
+			name:      "staff and principal",
+			a:         Job{Title: "Staff Platform Engineer", Company: "Acme"},
+			b:         Job{Title: "Principal Platform Engineer", Company: "Acme"},
+			wantMerge: false,
+		},
+		{
+			name:      "same title at another company",
+			a:         Job{Title: "Backend Developer", Company: "Acme"},
+			b:         Job{Title: "Backend Developer", Company: "Globex"},
+			wantMerge: false,
+		},
+		{
+			name:      "different role",
+			a:         Job{Title: "Frontend Developer", Company: "Acme"},
+			b:         Job{Title: "Backend Developer", Company: "Acme"},
+			wantMerge: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deduplicateJobs([]Job{tt.a, tt.b})
+			if merged := len(got) == 1; merged != tt.wantMerge {
+				t.Errorf("%q at %q and %q at %q merged = %v, want %v", tt.a.Title, tt.a.Company, tt.b.Title, tt.b.Company, merged, tt.wantMerge)
+			}
+		})
+	}
+}
+
+func TestCanonicalJobURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "https://jobs.example.com/view/42", "jobs.example.com/view/42"},
+		{"scheme, www and case", "HTTP://WWW.Jobs.Example.com/view/42", "jobs.example.com/view/42"},
+		{"trailing slash", "https://jobs.example.com/view/42/", "jobs.example.com/view/42"},
+		{"tracking params", "https://jobs.example.com/view/42?utm_source=x&utm_medium=y&gclid=z&ref=feed", "jobs.example.com/view/42"},
+		{"meaningful params sorted", "https://jobs.example.com/view?lang=en&id=42&src=mail", "jobs.example.com/view?id=42&lang=en"},
+		{"not a url", "not a url", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalJobURL(tt.in); got != tt.want {
+				t.Errorf("canonicalJobURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCompany(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Acme", "acme"},
+		{"Acme Inc", "acme"},
+		{"Acme, Inc.", "acme"},
+		{"The Acme Company Ltd", "acme"},
+		{"Acme Software GmbH", "acme software"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := normalizeCompany(tt.in); got != tt.want {
+				t.Errorf("normalizeCompany(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// A duplicate keeps the first copy's identity and fills in what only the other copy has
+func TestDeduplicateJobsMergesFields(t *testing.T) {
+	jobs := []Job{
+		{
+			Title: "Senior Go Developer", Company: "Acme", Source: "Adzuna",
+			JobUrl: "https://adzuna.example/1", Location: "Not specified",
+			Description: "Go.", Tags: []string{"go"},
+		},
+		{
+			Title: "Sr. Go Developer", Company: "Acme Inc", Source: "RemoteOK",
+			Location: "Berlin", Salary: "€80k", JobType: "Full-time",
+			Description: "Build Go services for our billing platform.", Tags: []string{"Go", "postgres"},
+		},
+	}
+
+	got := deduplicateJobs(jobs)
+	want := []Job{{
+		Title: "Senior Go Developer", Company: "Acme", Source: "Adzuna",
+		JobUrl: "https://adzuna.example/1", Location: "Berlin", Salary: "€80k", JobType: "Full-time",
+		Description: "Build Go services for our billing platform.", Tags: []string{"go", "postgres"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged = %+v, want %+v", got, want)
+	}
+}
+
+// Duplicates from the first tier don't count toward the limit, the next tier is still asked
+func TestFetchJobRecommendationsCountsUniqueJobs(t *testing.T) {
+	t.Setenv("JOB_PROVIDERS", "dedup-a,dedup-b,dedup-c")
+	t.Setenv("JOB_PROVIDERS_DISABLED", "")
+
+	same := Job{Title: "Go Developer", Company: "Acme", JobUrl: "https://acme.example/jobs/1"}
+	other := Job{Title: "Platform Engineer", Company: "Globex", JobUrl: "https://globex.example/jobs/2"}
+	secondTier := false
+	providers := []JobProvider{
+		testProvider(t, "dedup-a", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			return []Job{same}, nil
+		}),
+		testProvider(t, "dedup-b", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			return []Job{same}, nil
+		}),
+		NewJobProvider("dedup-c", 99, func() bool { return true }, func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			secondTier = true
+			return []Job{other}, nil
+		}),
+	}
+	for _, p := range providers {
+		RegisterJobProvider(p)
+		t.Cleanup(func() { UnregisterJobProvider(p.Name()) })
+	}
+
+	jobs, err := FetchJobRecommendations(context.Background(), []string{"go"}, 2, JobPreferences{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !secondTier {
+		t.Error("second tier was skipped, the duplicate counted twice")
+	}
+	if len(jobs) != 2 {
+		t.Errorf("got %d jobs, want 2", len(jobs))
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"backend/ats"
+	"backend/config"
+	"backend/models"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Errors of ResolveJobDescription
+var (
+	ErrJobDescriptionNotFound = errors.New("job description not found")
+	ErrJobDescriptionConflict = errors.New("send either job_description or job_description_id, not both")
+)
+
+// Words that mark "nice to have" skills, in a heading for the lines below it or in the line itself
+var preferredSkillMarkers = []string{
+	"preferred", "nice to have", "nice-to-have", "good to have", "bonus", "a plus", "desirable",
+	"ideally", "optional",
+}
+
+// maxJobDescriptionHeading is the longest line treated as a heading
+const maxJobDescriptionHeading = 60
+
+// sentenceEnd splits a line into sentences, so "Go required. Kafka is a plus." marks only Kafka as preferred
+var sentenceEnd = regexp.MustCompile(`[.;!?]\s+`)
+
+// jobDescriptionHeadings are headings recognized without markup or a trailing colon
+var jobDescriptionHeadings = map[string]bool{
+	"requirements": true, "required": true, "required skills": true, "must have": true,
+	"must-have": true, "qualifications": true, "minimum qualifications": true,
+	"basic qualifications": true, "preferred qualifications": true, "preferred": true,
+	"preferred skills": true, "nice to have": true, "nice-to-have": true, "good to have": true,
+	"bonus": true, "bonus points": true, "responsibilities": true, "what you'll do": true,
+	"what you will do": true, "about you": true, "about the role": true, "skills": true,
+	"tech stack": true, "benefits": true, "perks": true,
+}
+
+// ParseJobDescriptionSkills returns the dictionary skills a job description asks for,
+// split into required and preferred ones. Skills under a "Preferred" or "Nice to have"
+// heading, or in a line saying so ("Kafka is a plus"), are preferred; a skill that is
+// also required elsewhere counts as required.
+func ParseJobDescriptionSkills(text string) (required, preferred []string) {
+	var requiredText, preferredText strings.Builder
+	inPreferred := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if isJobDescriptionHeading(line) {
+			inPreferred = hasAnyWord(line, preferredSkillMarkers)
+		}
+		for _, sentence := range sentenceEnd.Split(line, -1) {
+			if inPreferred || hasAnyWord(sentence, preferredSkillMarkers) {
+				preferredText.WriteString(sentence + "\n")
+			} else {
+				requiredText.WriteString(sentence + "\n")
+			}
+		}
+	}
+
+	required = ats.ExtractSkills(requiredText.String())
+	isRequired := make(map[string]bool, len(required))
+	for _, skill := range required {
+		isRequired[skill] = true
+	}
+	preferred = []string{}
+	for _, skill := range ats.ExtractSkills(preferredText.String()) {
+		if !isRequired[skill] {
+			preferred = append(preferred, skill)
+		}
+	}
+	return required, preferred
+}
+
+// isJobDescriptionHeading reports whether a line is a heading like "Requirements:",
+// "## Nice to have" or "**Bonus points**", list items never are
+func isJobDescriptionHeading(line string) bool {
+	if utf8.RuneCountInString(line) > maxJobDescriptionHeading {
+		return false
+	}
+	markup := strings.HasPrefix(line, "#") || strings.HasPrefix(line, "**")
+	if !markup && bulletPrefix.MatchString(line) {
+		return false
+	}
+	if markup || strings.HasSuffix(line, ":") {
+		return true
+	}
+	h := strings.ToLower(strings.Join(strings.Fields(line), " "))
+	return jobDescriptionHeadings[h]
+}
+
+// ApplyJobDescriptionSkills parses the skills of a job description's raw text into its skill columns
+func ApplyJobDescriptionSkills(jd *models.JobDescription) {
+	required, preferred := ParseJobDescriptionSkills(jd.RawText)
+	requiredJSON, _ := json.Marshal(required)
+	preferredJSON, _ := json.Marshal(preferred)
+	jd.RequiredSkills = string(requiredJSON)
+	jd.PreferredSkills = string(preferredJSON)
+}
+
+// UserJobDescription returns a saved job description of the user
+func UserJobDescription(userId, id uint) (*models.JobDescription, error) {
+	var jd models.JobDescription
+	err := config.DB.Where("id = ? AND user_id = ?", id, userId).First(&jd).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobDescriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &jd, nil
+}
+
+// ResolveJobDescription returns the job description text of an upload or re-analysis:
+// the raw text sent, or the saved job description with id (0 for none) and its id
+func ResolveJobDescription(userId uint, text string, id uint) (string, *uint, error) {
+	if id == 0 {
+		return text, nil, nil
+	}
+	if strings.TrimSpace(text) != "" {
+		return "", nil, ErrJobDescriptionConflict
+	}
+	jd, err := UserJobDescription(userId, id)
+	if err != nil {
+		return "", nil, err
+	}
+	return jd.RawText, &jd.Id, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsJobDescriptionHeading(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"Requirements:", true},
+		{"## Nice to have", true},
+		{"**Bonus points**", true},
+		{"Preferred Qualifications", true},
+		{"What you'll do", true},
+		{"Our stack:", true},
+		{"- Requirements:", false},
+		{"• Go", false},
+		{"3. Kafka", false},
+		{"We are looking for a backend engineer to join our payments team:", false},
+		{"Experience with Go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := isJobDescriptionHeading(tt.line); got != tt.want {
+				t.Errorf("isJobDescriptionHeading(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJobDescriptionSkills(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		wantRequired  []string
+		wantPreferred []string
+	}{
+		{
+			name:          "no headings",
+			text:          "We need Go, Docker and PostgreSQL.",
+			wantRequired:  []string{"docker", "go", "postgresql"},
+			wantPreferred: []string{},
+		},
+		{
+			name:          "preferred heading",
+			text:          "Requirements:\n- Go\n- Docker\n\nNice to have:\n- Kafka\n- Terraform",
+			wantRequired:  []string{"docker", "go"},
+			wantPreferred: []string{"kafka", "terraform"},
+		},
+		{
+			name:          "markdown headings",
+			text:          "## Must have\n* Python\n## Bonus\n* AWS\n## Responsibilities\n* Own the Django services",
+			wantRequired:  []string{"django", "python"},
+			wantPreferred: []string{"aws"},
+		},
+		{
+			name:          "a plus in the same line",
+			text:          "Go required. Kafka is a plus.",
+			wantRequired:  []string{"go"},
+			wantPreferred: []string{"kafka"},
+		},
+		{
+			name:          "ideally in the same line",
+			text:          "Strong Python; ideally some Rust! React experience?",
+			wantRequired:  []string{"python", "react"},
+			wantPreferred: []string{"rust"},
+		},
+		{
+			name:          "no split inside a term",
+			text:          "Node.js and ASP.NET, Terraform ideally",
+			wantRequired:  []string{},
+			wantPreferred: []string{"asp.net", "node", "terraform"},
+		},
+		{
+			name:          "required wins over preferred",
+			text:          "Requirements:\n- Go\n- Kubernetes\nPreferred:\n- Kubernetes\n- Helm",
+			wantRequired:  []string{"go", "kubernetes"},
+			wantPreferred: []string{"helm"},
+		},
+		{
+			name:          "required wins within a line",
+			text:          "Docker is a must. Docker Swarm would be a plus.",
+			wantRequired:  []string{"docker"},
+			wantPreferred: []string{},
+		},
+		{
+			name:          "empty",
+			text:          "",
+			wantRequired:  []string{},
+			wantPreferred: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			required, preferred := ParseJobDescriptionSkills(tt.text)
+			if !reflect.DeepEqual(required, tt.wantRequired) {
+				t.Errorf("required = %q, want %q", required, tt.wantRequired)
+			}
+			if !reflect.DeepEqual(preferred, tt.wantPreferred) {
+				t.Errorf("preferred = %q, want %q", preferred, tt.wantPreferred)
+			}
+		})
+	}
+}
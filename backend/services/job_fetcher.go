@@ -1,14 +1,15 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -22,11 +23,20 @@ type Job struct {
 	JobUrl      string `json:"job_url"`
 	PostedDate  string `json:"posted_date"`
 	JobType     string `json:"job_type"`
+	Source      string `json:"source"`    // name of the provider the posting came from
+	SourceId    string `json:"source_id"` // the provider's own ID for the posting
+
+	Tags          []string `json:"tags,omitempty"` // provider tags/keywords, used for ranking
+	Score         float64  `json:"score"`          // relevance score (0-100) set by rankJobs
+	MatchedSkills []string `json:"matched_skills"` // resume skills found in the posting
+
+	Synthetic bool `json:"synthetic"` // generated by the fallback, not a real posting
 }
 
 // AdzunaResponse represents the response from Adzuna API
 type AdzunaResponse struct {
 	Results []struct {
+		Id      string `json:"id"`
 		Title   string `json:"title"`
 		Company struct {
 			DisplayName string `json:"display_name"`
@@ -50,8 +60,34 @@ type APIResult struct {
 	Error  error
 }
 
-// FetchJobRecommendations fetches real-time jobs using parallel API calls
-func FetchJobRecommendations(skills []string, limit int) ([]Job, error) {
+// defaultJobFetchTimeout is the overall deadline for fetching jobs when JOB_FETCH_TIMEOUT is not set
+const defaultJobFetchTimeout = 12 * time.Second
+
+// jobHTTPClient is shared by all job providers. Requests are bounded by the
+// caller's context; the client timeout is only a safety net for callers without a deadline.
+var jobHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// jobFetchTimeout reads the overall job fetch deadline from JOB_FETCH_TIMEOUT (e.g. "8s")
+func jobFetchTimeout() time.Duration {
+	if v := os.Getenv("JOB_FETCH_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		fmt.Printf("⚠️  Invalid JOB_FETCH_TIMEOUT %q, using %s\n", v, defaultJobFetchTimeout)
+	}
+	return defaultJobFetchTimeout
+}
+
+// candidatesPerJob is how many candidates we ask providers for per requested job,
+// so ranking has more than the first-come results to choose from
+const candidatesPerJob = 3
+
+// FetchJobRecommendations fetches real-time jobs using parallel API calls and
+// returns the most relevant ones for the given skills and preferences.
+// All provider calls share one overall deadline (JOB_FETCH_TIMEOUT); when it hits,
+// whatever jobs have arrived so far are returned. If ctx is cancelled (e.g. the
+// client disconnected) the fetch stops and ctx.Err() is returned.
+func FetchJobRecommendations(ctx context.Context, skills []string, limit int, prefs JobPreferences) ([]Job, error) {
 	if limit <= 0 || limit > 10 {
 		limit = 5
 	}
@@ -59,151 +95,150 @@ func FetchJobRecommendations(skills []string, limit int) ([]Job, error) {
 	fmt.Println("\n🚀 Starting Parallel Job Fetch System")
 	fmt.Printf("📊 Target: %d jobs from skills: %v\n", limit, skills)
 
-	// Priority 1: Fast, reliable APIs (run in parallel)
-	priority1APIs := []func([]string, int) ([]Job, error){
-		fetchFromRemoteOK,          // Free, no auth, tech jobs
-		fetchFromArbeitnow,         // Free, no auth, EU + US jobs
-		fetchFromTheMuse,           // Free, no auth, curated jobs
-		fetchFromAdzunaIfAvailable, // Only if credentials available
-	}
+	fetchCtx, cancel := context.WithTimeout(ctx, jobFetchTimeout())
+	defer cancel()
 
-	// Priority 2: Backup APIs (run in parallel if Priority 1 fails)
-	priority2APIs := []func([]string, int) ([]Job, error){
-		fetchFromFindwork,           // Free, no auth, tech focus
-		fetchFromJoobleIfAvailable,  // Only if API key available
-		fetchFromJSearchIfAvailable, // Only if RapidAPI key available
-	}
+	// Run each priority tier in parallel, falling through to the next tier
+	// only while we still don't have enough unique jobs
+	candidateLimit := limit * candidatesPerJob
+	var allJobs []Job
+	for _, tier := range ActiveJobProviders() {
+		if fetchCtx.Err() != nil {
+			break
+		}
+
+		fmt.Printf("\n🔵 Priority %d: Fetching from %d APIs simultaneously...\n", tier[0].Priority(), len(tier))
+		jobs, _ := fetchParallel(fetchCtx, tier, skills, candidateLimit)
+		// The same posting from several boards only counts once
+		allJobs = deduplicateJobs(append(allJobs, jobs...))
 
-	// Try Priority 1 APIs in parallel
-	fmt.Println("\n🔵 Priority 1: Fetching from 3-4 APIs simultaneously...")
-	allJobs, totalFetched := fetchParallel(priority1APIs, skills, limit)
+		if len(allJobs) >= limit {
+			fmt.Printf("\n✅ SUCCESS: Got %d jobs after Priority %d APIs\n", len(allJobs), tier[0].Priority())
+			return selectTopJobs(allJobs, skills, prefs, limit), nil
+		}
+		fmt.Printf("\n🟡 Priority %d only got %d jobs, trying next tier...\n", tier[0].Priority(), len(allJobs))
+	}
 
-	// If we got enough jobs, return them
-	if len(allJobs) >= limit {
-		fmt.Printf("\n✅ SUCCESS: Got %d jobs from Priority 1 APIs\n", len(allJobs))
-		return deduplicateJobs(allJobs, limit), nil
+	// The caller went away, nobody is waiting for the result
+	if ctx.Err() != nil {
+		fmt.Println("\n🛑 Job fetch cancelled:", ctx.Err())
+		return nil, ctx.Err()
 	}
 
-	// If Priority 1 didn't get enough jobs, try Priority 2
-	if totalFetched < limit {
-		fmt.Printf("\n🟡 Priority 1 only got %d jobs, trying Priority 2 APIs...\n", totalFetched)
-		moreJobs, _ := fetchParallel(priority2APIs, skills, limit-totalFetched)
-		allJobs = append(allJobs, moreJobs...)
+	if fetchCtx.Err() == context.DeadlineExceeded {
+		fmt.Printf("\n⏱️  Job fetch deadline reached, returning %d partial results\n", len(allJobs))
 	}
 
-	// Deduplicate and return
 	if len(allJobs) > 0 {
 		fmt.Printf("\n✅ TOTAL: Fetched %d jobs from all APIs\n", len(allJobs))
-		return deduplicateJobs(allJobs, limit), nil
+		return selectTopJobs(allJobs, skills, prefs, limit), nil
 	}
 
-	// Final fallback: generate sample jobs
-	fmt.Println("\n📝 All APIs failed, generating sample jobs")
-	return generateSampleJobs(skills, limit), nil
+	// Final fallback, depending on JOB_FALLBACK_MODE
+	switch mode := JobFallbackMode(); mode {
+	case FallbackDemo:
+		fmt.Println("\n📝 All APIs failed, generating demo jobs")
+		return rankJobs(generateSampleJobs(skills, limit), skills, prefs), nil
+	case FallbackPlaceholder:
+		fmt.Println("\n📝 All APIs failed, returning job board search placeholders")
+		return PlaceholderJobs(skills, limit), nil
+	default:
+		fmt.Println("\n📭 All APIs failed, no job recommendations")
+		return []Job{}, nil
+	}
 }
 
-// fetchParallel runs multiple API fetchers in parallel and collects results
-func fetchParallel(apis []func([]string, int) ([]Job, error), skills []string, limit int) ([]Job, int) {
-	var wg sync.WaitGroup
-	results := make(chan APIResult, len(apis))
+// Fallback modes used when every job provider fails (JOB_FALLBACK_MODE)
+const (
+	FallbackOff         = "off"         // return no jobs
+	FallbackPlaceholder = "placeholder" // return job board search links, never stored
+	FallbackDemo        = "demo"        // return fabricated demo jobs, stored like real ones
+)
+
+// JobFallbackMode returns the configured fallback mode, "off" by default
+func JobFallbackMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("JOB_FALLBACK_MODE"))); mode {
+	case FallbackPlaceholder, FallbackDemo:
+		return mode
+	default:
+		return FallbackOff
+	}
+}
+
+// ShouldPersistJob reports whether a job may be stored as a recommendation.
+// Synthetic jobs are only stored in demo mode.
+func ShouldPersistJob(job Job) bool {
+	return !job.Synthetic || JobFallbackMode() == FallbackDemo
+}
+
+// fetchParallel runs multiple job providers in parallel and collects results.
+// It stops waiting when ctx is done and returns the jobs collected so far and how
+// many providers failed or didn't answer in time.
+func fetchParallel(ctx context.Context, providers []JobProvider, skills []string, limit int) ([]Job, int) {
+	// Buffered so providers still running after ctx is done never block
+	results := make(chan APIResult, len(providers))
 
 	// Launch all API calls in parallel
-	for _, apiFunc := range apis {
-		wg.Add(1)
-		go func(fn func([]string, int) ([]Job, error)) {
-			defer wg.Done()
-			jobs, err := fn(skills, limit)
+	for _, provider := range providers {
+		go func(p JobProvider) {
+			jobs, err := fetchWithBreaker(ctx, p, skills, limit)
+			// Attribute jobs from providers that don't set their own source
+			for i := range jobs {
+				if jobs[i].Source == "" {
+					jobs[i].Source = p.Name()
+				}
+			}
 			results <- APIResult{
-				Jobs:  jobs,
-				Error: err,
+				Jobs:   jobs,
+				Source: p.Name(),
+				Error:  err,
 			}
-		}(apiFunc)
+		}(provider)
 	}
 
-	// Wait for all goroutines to complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect all results
+	// Collect results until every provider answered or the deadline hits
 	var allJobs []Job
 	successCount := 0
 	failCount := 0
 
-	for result := range results {
-		if result.Error == nil && len(result.Jobs) > 0 {
+collect:
+	for pending := len(providers); pending > 0; pending-- {
+		select {
+		case result := <-results:
+			if result.Error != nil {
+				failCount++
+				fmt.Printf("  ✗ %s failed: %v\n", result.Source, result.Error)
+				continue
+			}
+			// Matching nothing is a successful search
 			allJobs = append(allJobs, result.Jobs...)
 			successCount++
-			fmt.Printf("  ✓ API returned %d jobs\n", len(result.Jobs))
-		} else {
-			failCount++
-			fmt.Printf("  ✗ API failed or returned 0 jobs\n")
+			fmt.Printf("  ✓ %s returned %d jobs\n", result.Source, len(result.Jobs))
+		case <-ctx.Done():
+			fmt.Printf("  ⏱️  %d APIs did not answer in time: %v\n", pending, ctx.Err())
+			failCount += pending
+			break collect
 		}
 	}
 
 	fmt.Printf("📈 Parallel fetch complete: %d succeeded, %d failed\n", successCount, failCount)
-	return allJobs, len(allJobs)
+	return allJobs, failCount
 }
 
-// deduplicateJobs removes duplicate jobs based on title + company and limits to desired count
-func deduplicateJobs(jobs []Job, limit int) []Job {
-	seen := make(map[string]bool)
-	unique := make([]Job, 0, limit)
-
-	for _, job := range jobs {
-		// Create a unique key based on title and company
-		key := strings.ToLower(fmt.Sprintf("%s|%s", job.Title, job.Company))
-
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, job)
-
-			// Stop when we reach the desired limit
-			if len(unique) >= limit {
-				break
-			}
-		}
+// selectTopJobs ranks deduplicated jobs and keeps the best limit
+func selectTopJobs(jobs []Job, skills []string, prefs JobPreferences, limit int) []Job {
+	ranked := rankJobs(jobs, skills, prefs)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
 	}
-
-	fmt.Printf("🔧 Deduplication: %d jobs → %d unique jobs\n", len(jobs), len(unique))
-	return unique
+	return ranked
 }
 
-// fetchFromAdzunaIfAvailable fetches from Adzuna only if credentials are available
-func fetchFromAdzunaIfAvailable(skills []string, limit int) ([]Job, error) {
+// fetchFromAdzuna fetches jobs from Adzuna API (requires ADZUNA_APP_ID and ADZUNA_APP_KEY)
+func fetchFromAdzuna(ctx context.Context, skills []string, limit int) ([]Job, error) {
 	appId := os.Getenv("ADZUNA_APP_ID")
 	appKey := os.Getenv("ADZUNA_APP_KEY")
 
-	if appId == "" || appKey == "" {
-		return nil, fmt.Errorf("Adzuna credentials not available")
-	}
-
-	return fetchFromAdzuna(skills, limit, appId, appKey)
-}
-
-// fetchFromJoobleIfAvailable fetches from Jooble only if API key is available
-func fetchFromJoobleIfAvailable(skills []string, limit int) ([]Job, error) {
-	apiKey := os.Getenv("JOOBLE_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("Jooble API key not available")
-	}
-
-	return fetchFromJooble(skills, limit, apiKey)
-}
-
-// fetchFromJSearchIfAvailable fetches from JSearch only if RapidAPI key is available
-func fetchFromJSearchIfAvailable(skills []string, limit int) ([]Job, error) {
-	apiKey := os.Getenv("RAPIDAPI_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("RapidAPI key not available")
-	}
-
-	return fetchFromJSearch(skills, limit, apiKey)
-}
-
-// fetchFromAdzuna fetches jobs from Adzuna API
-func fetchFromAdzuna(skills []string, limit int, appId, appKey string) ([]Job, error) {
 	// Build search query from skills
 	query := strings.Join(skills, " OR ")
 	if len(query) > 200 {
@@ -226,8 +261,12 @@ func fetchFromAdzuna(skills []string, limit int, appId, appKey string) ([]Job, e
 
 	fullURL := fmt.Sprintf("%s?%s", apiURL, params.Encode())
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(fullURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := jobHTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -248,7 +287,7 @@ func fetchFromAdzuna(skills []string, limit int, appId, appKey string) ([]Job, e
 	}
 
 	if len(adzunaResp.Results) == 0 {
-		return nil, fmt.Errorf("no jobs found")
+		return []Job{}, nil
 	}
 
 	// Convert to our Job struct
@@ -276,6 +315,8 @@ func fetchFromAdzuna(skills []string, limit int, appId, appKey string) ([]Job, e
 			JobUrl:      result.RedirectUrl,
 			PostedDate:  result.Created.Format("2006-01-02"),
 			JobType:     result.ContractType,
+			Source:      "adzuna",
+			SourceId:    result.Id,
 		})
 	}
 
@@ -284,7 +325,7 @@ func fetchFromAdzuna(skills []string, limit int, appId, appKey string) ([]Job, e
 }
 
 // fetchFromTheMuse uses The Muse API (free, no auth)
-func fetchFromTheMuse(skills []string, limit int) ([]Job, error) {
+func fetchFromTheMuse(ctx context.Context, skills []string, limit int) ([]Job, error) {
 	apiURL := "https://www.themuse.com/api/public/jobs"
 	params := url.Values{}
 	params.Add("page", "0")
@@ -300,8 +341,12 @@ func fetchFromTheMuse(skills []string, limit int) ([]Job, error) {
 
 	fullURL := fmt.Sprintf("%s?%s", apiURL, params.Encode())
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(fullURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := jobHTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -318,6 +363,7 @@ func fetchFromTheMuse(skills []string, limit int) ([]Job, error) {
 
 	var museResp struct {
 		Results []struct {
+			Id      int    `json:"id"`
 			Name    string `json:"name"`
 			Company struct {
 				Name string `json:"name"`
@@ -357,6 +403,8 @@ func fetchFromTheMuse(skills []string, limit int) ([]Job, error) {
 			JobUrl:      result.Refs.LandingPage,
 			PostedDate:  result.PublicationDate,
 			JobType:     "Full-time",
+			Source:      "themuse",
+			SourceId:    strconv.Itoa(result.Id),
 		})
 	}
 
@@ -364,8 +412,9 @@ func fetchFromTheMuse(skills []string, limit int) ([]Job, error) {
 	return jobs, nil
 }
 
-// fetchFromJSearch uses JSearch API (RapidAPI)
-func fetchFromJSearch(skills []string, limit int, apiKey string) ([]Job, error) {
+// fetchFromJSearch uses JSearch API (RapidAPI, requires RAPIDAPI_KEY)
+func fetchFromJSearch(ctx context.Context, skills []string, limit int) ([]Job, error) {
+	apiKey := os.Getenv("RAPIDAPI_KEY")
 	query := strings.Join(skills[:min(3, len(skills))], " ")
 	apiURL := "https://jsearch.p.rapidapi.com/search"
 
@@ -375,7 +424,7 @@ func fetchFromJSearch(skills []string, limit int, apiKey string) ([]Job, error)
 
 	fullURL := fmt.Sprintf("%s?%s", apiURL, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -383,8 +432,7 @@ func fetchFromJSearch(skills []string, limit int, apiKey string) ([]Job, error)
 	req.Header.Add("X-RapidAPI-Key", apiKey)
 	req.Header.Add("X-RapidAPI-Host", "jsearch.p.rapidapi.com")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := jobHTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -401,6 +449,7 @@ func fetchFromJSearch(skills []string, limit int, apiKey string) ([]Job, error)
 
 	var jsearchResp struct {
 		Data []struct {
+			JobId             string `json:"job_id"`
 			JobTitle          string `json:"job_title"`
 			EmployerName      string `json:"employer_name"`
 			JobCity           string `json:"job_city"`
@@ -443,6 +492,8 @@ func fetchFromJSearch(skills []string, limit int, apiKey string) ([]Job, error)
 			JobUrl:      result.JobApplyLink,
 			PostedDate:  result.JobPostedDate,
 			JobType:     result.JobEmploymentType,
+			Source:      "jsearch",
+			SourceId:    result.JobId,
 		})
 	}
 
@@ -450,8 +501,10 @@ func fetchFromJSearch(skills []string, limit int, apiKey string) ([]Job, error)
 	return jobs, nil
 }
 
-// fetchFromJooble fetches jobs from Jooble API (requires API key)
-func fetchFromJooble(skills []string, limit int, apiKey string) ([]Job, error) {
+// fetchFromJooble fetches jobs from Jooble API (requires JOOBLE_API_KEY)
+func fetchFromJooble(ctx context.Context, skills []string, limit int) ([]Job, error) {
+	apiKey := os.Getenv("JOOBLE_API_KEY")
+
 	// Build keywords from top skills
 	keywords := strings.Join(skills[:min(5, len(skills))], " ")
 
@@ -470,15 +523,14 @@ func fetchFromJooble(skills []string, limit int, apiKey string) ([]Job, error) {
 		return nil, err
 	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(string(jsonBody)))
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(string(jsonBody)))
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := jobHTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -497,15 +549,16 @@ func fetchFromJooble(skills []string, limit int, apiKey string) ([]Job, error) {
 	var joobleResp struct {
 		TotalCount int `json:"totalCount"`
 		Jobs       []struct {
-			Title    string `json:"title"`
-			Location string `json:"location"`
-			Snippet  string `json:"snippet"`
-			Salary   string `json:"salary"`
-			Source   string `json:"source"`
-			Type     string `json:"type"`
-			Link     string `json:"link"`
-			Company  string `json:"company"`
-			Updated  string `json:"updated"`
+			Id       json.Number `json:"id"`
+			Title    string      `json:"title"`
+			Location string      `json:"location"`
+			Snippet  string      `json:"snippet"`
+			Salary   string      `json:"salary"`
+			Source   string      `json:"source"`
+			Type     string      `json:"type"`
+			Link     string      `json:"link"`
+			Company  string      `json:"company"`
+			Updated  string      `json:"updated"`
 		} `json:"jobs"`
 	}
 
@@ -514,7 +567,7 @@ func fetchFromJooble(skills []string, limit int, apiKey string) ([]Job, error) {
 	}
 
 	if len(joobleResp.Jobs) == 0 {
-		return nil, fmt.Errorf("no jobs found")
+		return []Job{}, nil
 	}
 
 	jobs := make([]Job, 0, min(limit, len(joobleResp.Jobs)))
@@ -548,6 +601,8 @@ func fetchFromJooble(skills []string, limit int, apiKey string) ([]Job, error) {
 			JobUrl:      result.Link,
 			PostedDate:  result.Updated,
 			JobType:     jobType,
+			Source:      "jooble",
+			SourceId:    result.Id.String(),
 		})
 	}
 
@@ -556,21 +611,17 @@ func fetchFromJooble(skills []string, limit int, apiKey string) ([]Job, error) {
 }
 
 // fetchFromArbeitnow fetches jobs from Arbeitnow API (free, no auth, EU + US)
-func fetchFromArbeitnow(skills []string, limit int) ([]Job, error) {
+func fetchFromArbeitnow(ctx context.Context, skills []string, limit int) ([]Job, error) {
 	apiURL := "https://www.arbeitnow.com/api/job-board-api"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(apiURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	// The feed doesn't depend on skills, so it is cached and filtered locally
+	body, err := cachedFeed(ctx, "arbeitnow", func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return downloadFeed(req)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -653,6 +704,9 @@ func fetchFromArbeitnow(skills []string, limit int) ([]Job, error) {
 			JobUrl:      item.URL,
 			PostedDate:  postedDate,
 			JobType:     jobType,
+			Source:      "arbeitnow",
+			SourceId:    item.Slug,
+			Tags:        item.Tags,
 		})
 	}
 
@@ -661,30 +715,21 @@ func fetchFromArbeitnow(skills []string, limit int) ([]Job, error) {
 }
 
 // fetchFromFindwork fetches jobs from Findwork API (free, no auth, tech focus)
-func fetchFromFindwork(skills []string, limit int) ([]Job, error) {
+func fetchFromFindwork(ctx context.Context, skills []string, limit int) ([]Job, error) {
 	// Findwork API - free tier, no auth
 	apiURL := "https://findwork.dev/api/jobs/"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Add required headers
-	req.Header.Add("Authorization", "Token test-token") // Public test token
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
+	// The feed doesn't depend on skills, so it is cached and filtered locally
+	body, err := cachedFeed(ctx, "findwork", func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(resp.Body)
+		// Add required headers
+		req.Header.Add("Authorization", "Token test-token") // Public test token
+		return downloadFeed(req)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -760,6 +805,9 @@ func fetchFromFindwork(skills []string, limit int) ([]Job, error) {
 			JobUrl:      item.URL,
 			PostedDate:  item.DatePosted,
 			JobType:     item.EmploymentType,
+			Source:      "findwork",
+			SourceId:    strconv.Itoa(item.ID),
+			Tags:        item.Keywords,
 		})
 	}
 
@@ -768,29 +816,20 @@ func fetchFromFindwork(skills []string, limit int) ([]Job, error) {
 }
 
 // fetchFromRemoteOK fetches tech jobs from RemoteOK (free, no auth)
-func fetchFromRemoteOK(skills []string, limit int) ([]Job, error) {
+func fetchFromRemoteOK(ctx context.Context, skills []string, limit int) ([]Job, error) {
 	apiURL := "https://remoteok.com/api"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// RemoteOK requires user agent
-	req.Header.Add("User-Agent", "SmartResume/1.0")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
+	// The feed doesn't depend on skills, so it is cached and filtered locally
+	body, err := cachedFeed(ctx, "remoteok", func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(resp.Body)
+		// RemoteOK requires user agent
+		req.Header.Add("User-Agent", "SmartResume/1.0")
+		return downloadFeed(req)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -821,6 +860,13 @@ func fetchFromRemoteOK(skills []string, limit int) ([]Job, error) {
 		position := fmt.Sprintf("%v", item["position"])
 		tags := fmt.Sprintf("%v", item["tags"])
 
+		var tagList []string
+		if t, ok := item["tags"].([]interface{}); ok {
+			for _, tag := range t {
+				tagList = append(tagList, fmt.Sprintf("%v", tag))
+			}
+		}
+
 		matchFound := false
 		for _, skill := range skillsLower {
 			if strings.Contains(strings.ToLower(position), skill) ||
@@ -865,6 +911,11 @@ func fetchFromRemoteOK(skills []string, limit int) ([]Job, error) {
 			}
 		}
 
+		id := ""
+		if i, ok := item["id"]; ok && i != nil {
+			id = formatJSONValue(i)
+		}
+
 		date := ""
 		if d, ok := item["date"]; ok {
 			date = fmt.Sprintf("%v", d)
@@ -879,12 +930,18 @@ func fetchFromRemoteOK(skills []string, limit int) ([]Job, error) {
 			JobUrl:      url,
 			PostedDate:  date,
 			JobType:     "Remote",
+			Source:      "remoteok",
+			SourceId:    id,
+			Tags:        tagList,
 		})
 	}
 
 	fmt.Printf("  ✓ RemoteOK API: %d jobs\n", len(jobs))
 	return jobs, nil
-} // generateSampleJobs creates sample job listings based on skills
+}
+
+// generateSampleJobs creates sample job listings based on skills
+// These are fake companies and generic URLs, only meant for demos (JOB_FALLBACK_MODE=demo).
 func generateSampleJobs(skills []string, limit int) []Job {
 	fmt.Println("📝 Generating sample job recommendations based on skills")
 
@@ -893,6 +950,9 @@ func generateSampleJobs(skills []string, limit int) []Job {
 	if len(topSkills) > 3 {
 		topSkills = topSkills[:3]
 	}
+	if len(topSkills) == 0 {
+		topSkills = []string{"software"}
+	}
 
 	jobs := []Job{
 		{
@@ -904,6 +964,8 @@ func generateSampleJobs(skills []string, limit int) []Job {
 			JobUrl:      "https://www.linkedin.com/jobs/",
 			PostedDate:  time.Now().AddDate(0, 0, -2).Format("2006-01-02"),
 			JobType:     "Full-time",
+			Source:      "sample",
+			Synthetic:   true,
 		},
 		{
 			Title:       fmt.Sprintf("%s Software Engineer", capitalize(topSkills[0])),
@@ -914,6 +976,8 @@ func generateSampleJobs(skills []string, limit int) []Job {
 			JobUrl:      "https://www.indeed.com/",
 			PostedDate:  time.Now().AddDate(0, 0, -5).Format("2006-01-02"),
 			JobType:     "Full-time",
+			Source:      "sample",
+			Synthetic:   true,
 		},
 		{
 			Title:       "Full Stack Developer",
@@ -924,6 +988,8 @@ func generateSampleJobs(skills []string, limit int) []Job {
 			JobUrl:      "https://www.glassdoor.com/Job/",
 			PostedDate:  time.Now().AddDate(0, 0, -7).Format("2006-01-02"),
 			JobType:     "Full-time",
+			Source:      "sample",
+			Synthetic:   true,
 		},
 		{
 			Title:       fmt.Sprintf("Mid-Level %s Developer", capitalize(topSkills[0])),
@@ -934,6 +1000,8 @@ func generateSampleJobs(skills []string, limit int) []Job {
 			JobUrl:      "https://www.monster.com/jobs/",
 			PostedDate:  time.Now().AddDate(0, 0, -10).Format("2006-01-02"),
 			JobType:     "Full-time",
+			Source:      "sample",
+			Synthetic:   true,
 		},
 		{
 			Title:       "Software Development Engineer",
@@ -944,6 +1012,8 @@ func generateSampleJobs(skills []string, limit int) []Job {
 			JobUrl:      "https://www.dice.com/jobs/",
 			PostedDate:  time.Now().AddDate(0, 0, -3).Format("2006-01-02"),
 			JobType:     "Full-time",
+			Source:      "sample",
+			Synthetic:   true,
 		},
 	}
 
@@ -953,7 +1023,55 @@ func generateSampleJobs(skills []string, limit int) []Job {
 	return jobs
 }
 
+// PlaceholderJobs returns links to job board searches for the top skills.
+// They are flagged synthetic and are not real postings.
+func PlaceholderJobs(skills []string, limit int) []Job {
+	query := "software developer"
+	if len(skills) > 0 {
+		query = strings.Join(skills[:min(3, len(skills))], " ")
+	}
+
+	boards := []struct {
+		name      string
+		searchURL string
+	}{
+		{"LinkedIn", "https://www.linkedin.com/jobs/search/?keywords="},
+		{"Indeed", "https://www.indeed.com/jobs?q="},
+		{"Glassdoor", "https://www.glassdoor.com/Job/jobs.htm?sc.keyword="},
+	}
+
+	jobs := make([]Job, 0, min(limit, len(boards)))
+	for _, board := range boards[:min(limit, len(boards))] {
+		jobs = append(jobs, Job{
+			Title:         fmt.Sprintf("Search %s jobs on %s", query, board.name),
+			Company:       board.name,
+			Description:   "No live job postings could be fetched right now. This link opens a search on " + board.name + ".",
+			JobUrl:        board.searchURL + url.QueryEscape(query),
+			Source:        "placeholder",
+			MatchedSkills: []string{},
+			Synthetic:     true,
+		})
+	}
+	return jobs
+}
+
 // Helper functions
+
+// downloadFeed performs a job board request and returns the body of a 200 response
+func downloadFeed(req *http.Request) ([]byte, error) {
+	resp, err := jobHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
 func cleanDescription(desc string, maxLength int) string {
 	// Remove all HTML tags using regex-like approach
 	// Simple state machine to remove everything between < and >
@@ -1005,6 +1123,14 @@ func cleanDescription(desc string, maxLength int) string {
 	return desc
 }
 
+// formatJSONValue formats a decoded JSON scalar without float exponent notation for large IDs
+func formatJSONValue(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 func capitalize(s string) string {
 	if len(s) == 0 {
 		return s
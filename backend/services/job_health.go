@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"    // provider is healthy, calls go through
+	CircuitOpen     = "open"      // provider failed repeatedly, calls are skipped
+	CircuitHalfOpen = "half-open" // cooldown passed, one probe call is allowed
+)
+
+// Defaults, overridable with JOB_BREAKER_FAILURES, JOB_BREAKER_COOLDOWN and JOB_PROVIDER_TIMEOUT
+const (
+	defaultBreakerFailures = 3
+	defaultBreakerCooldown = 2 * time.Minute
+	defaultProviderTimeout = 10 * time.Second
+	latencyWindow          = 20
+)
+
+// ErrCircuitOpen is returned for providers skipped because their circuit is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// ProviderStatus is the health of a job provider as reported by GET /api/jobs/providers
+type ProviderStatus struct {
+	Name                string     `json:"name"`
+	Priority            int        `json:"priority"`
+	Enabled             bool       `json:"enabled"` // has credentials and allowed by config
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	AvgLatencyMs        int64      `json:"avg_latency_ms"`
+	Calls               int        `json:"calls"`
+}
+
+// circuitBreaker tracks recent failures and latency of one provider
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	lastError           string
+	lastErrorAt         time.Time
+	lastSuccessAt       time.Time
+	latencies           []time.Duration
+	calls               int
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*circuitBreaker)
+)
+
+// breakerFor returns the circuit breaker of a provider, creating it on first use
+func breakerFor(provider string) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	b, ok := breakers[provider]
+	if !ok {
+		b = &circuitBreaker{state: CircuitClosed}
+		breakers[provider] = b
+	}
+	return b
+}
+
+// allow reports whether a call may go through. After the cooldown an open circuit
+// lets exactly one probe call through (half-open) and waits for its result.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < breakerCooldown() {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// a probe is already running
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of a call
+func (b *circuitBreaker) record(err error, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.calls++
+	b.latencies = append(b.latencies, latency)
+	if len(b.latencies) > latencyWindow {
+		b.latencies = b.latencies[1:]
+	}
+
+	if err == nil {
+		b.state = CircuitClosed
+		b.consecutiveFailures = 0
+		b.lastSuccessAt = time.Now()
+		return
+	}
+
+	b.consecutiveFailures++
+	b.lastError = err.Error()
+	b.lastErrorAt = time.Now()
+
+	if b.state == CircuitHalfOpen || b.consecutiveFailures >= breakerFailures() {
+		if b.state != CircuitOpen {
+			fmt.Printf("  🔌 Circuit opened after %d failures: %v\n", b.consecutiveFailures, err)
+		}
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// release gives up a half-open probe whose result says nothing about the provider
+// (the caller went away), so the next call can probe again
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.state = CircuitOpen
+		b.openedAt = time.Time{}
+	}
+}
+
+// status returns a snapshot of the breaker
+func (b *circuitBreaker) status() ProviderStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := ProviderStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		LastError:           b.lastError,
+		Calls:               b.calls,
+	}
+	if !b.lastErrorAt.IsZero() {
+		t := b.lastErrorAt
+		status.LastErrorAt = &t
+	}
+	if !b.lastSuccessAt.IsZero() {
+		t := b.lastSuccessAt
+		status.LastSuccessAt = &t
+	}
+	if len(b.latencies) > 0 {
+		var total time.Duration
+		for _, l := range b.latencies {
+			total += l
+		}
+		status.AvgLatencyMs = (total / time.Duration(len(b.latencies))).Milliseconds()
+	}
+
+	// An expired open circuit will probe on the next call
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= breakerCooldown() {
+		status.State = CircuitHalfOpen
+	}
+	return status
+}
+
+// fetchWithBreaker calls a provider through its circuit breaker
+func fetchWithBreaker(ctx context.Context, p JobProvider, skills []string, limit int) ([]Job, error) {
+	breaker := breakerFor(p.Name())
+	if !breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	// The provider's own deadline, hitting it counts as a failure
+	callCtx, cancel := context.WithTimeout(ctx, providerTimeout())
+	defer cancel()
+
+	start := time.Now()
+	jobs, err := p.Fetch(callCtx, skills, limit)
+
+	if err != nil && ctx.Err() != nil {
+		// The caller went away or the overall JOB_FETCH_TIMEOUT hit, possibly just after
+		// this call started: not the provider's fault
+		breaker.release()
+		return jobs, err
+	}
+
+	// A search that matched nothing (e.g. niche skills) is a success: the provider is healthy
+	breaker.record(err, time.Since(start))
+	return jobs, err
+}
+
+// JobProviderStatuses reports the health of every registered provider
+func JobProviderStatuses() []ProviderStatus {
+	providers := RegisteredJobProviders()
+	statuses := make([]ProviderStatus, 0, len(providers))
+
+	for _, p := range providers {
+		status := breakerFor(p.Name()).status()
+		status.Name = p.Name()
+		status.Priority = p.Priority()
+		status.Enabled = providerAllowed(p.Name()) && p.Enabled()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func breakerFailures() int {
+	return envInt("JOB_BREAKER_FAILURES", defaultBreakerFailures)
+}
+
+func breakerCooldown() time.Duration {
+	return envDuration(defaultBreakerCooldown, "JOB_BREAKER_COOLDOWN")
+}
+
+// providerTimeout is the deadline of one provider call, JOB_PROVIDER_TIMEOUT
+func providerTimeout() time.Duration {
+	return envDuration(defaultProviderTimeout, "JOB_PROVIDER_TIMEOUT")
+}
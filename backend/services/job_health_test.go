@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testProvider registers nothing, it only builds a provider with a fresh breaker
+func testProvider(t *testing.T, name string, fetch FetchFunc) JobProvider {
+	t.Helper()
+	reset := func() {
+		breakersMu.Lock()
+		delete(breakers, name)
+		breakersMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+	return NewJobProvider(name, 0, func() bool { return true }, fetch)
+}
+
+func TestFetchWithBreakerCountsFailures(t *testing.T) {
+	t.Setenv("JOB_BREAKER_FAILURES", "2")
+
+	blocking := func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name         string
+		fetch        FetchFunc
+		parent       func() (context.Context, context.CancelFunc)
+		wantErr      bool
+		wantFailures int
+	}{
+		{
+			name: "no jobs found",
+			fetch: func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+				return []Job{}, nil
+			},
+			wantFailures: 0,
+		},
+		{
+			name:    "provider timeout",
+			fetch:   blocking,
+			wantErr: true,
+			// JOB_PROVIDER_TIMEOUT hits before the parent deadline
+			wantFailures: 1,
+		},
+		{
+			name:  "overall fetch deadline",
+			fetch: blocking,
+			parent: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			wantErr:      true,
+			wantFailures: 0,
+		},
+		{
+			name:  "caller cancelled",
+			fetch: blocking,
+			parent: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr:      true,
+			wantFailures: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.parent == nil {
+				t.Setenv("JOB_PROVIDER_TIMEOUT", "20ms")
+			} else {
+				t.Setenv("JOB_PROVIDER_TIMEOUT", "1m")
+			}
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.parent != nil {
+				ctx, cancel = tt.parent()
+			}
+			defer cancel()
+
+			p := testProvider(t, "test-"+tt.name, tt.fetch)
+			jobs, err := fetchWithBreaker(ctx, p, []string{"go"}, 10)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if len(jobs) != 0 {
+				t.Errorf("jobs = %d, want none", len(jobs))
+			}
+
+			b := breakerFor(p.Name())
+			b.mu.Lock()
+			failures, state := b.consecutiveFailures, b.state
+			b.mu.Unlock()
+			if failures != tt.wantFailures {
+				t.Errorf("consecutive failures = %d, want %d", failures, tt.wantFailures)
+			}
+			if state != CircuitClosed {
+				t.Errorf("state = %q, want %q", state, CircuitClosed)
+			}
+		})
+	}
+}
+
+// A provider that keeps finding nothing never opens its circuit
+func TestFetchWithBreakerEmptyResultsKeepCircuitClosed(t *testing.T) {
+	t.Setenv("JOB_BREAKER_FAILURES", "2")
+	p := testProvider(t, "test-empty", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+		return []Job{}, nil
+	})
+
+	for i := 0; i < 5; i++ {
+		if _, err := fetchWithBreaker(context.Background(), p, []string{"cobol"}, 10); err != nil {
+			t.Fatalf("call %d: err = %v, want nil", i, err)
+		}
+	}
+	if _, err := fetchWithBreaker(context.Background(), p, []string{"cobol"}, 10); err == ErrCircuitOpen {
+		t.Fatal("circuit opened on empty results")
+	}
+}
+
+// Finding nothing is a successful search, whether the provider returns an empty or a nil slice
+func TestFetchParallelEmptyResultsSucceed(t *testing.T) {
+	providers := []JobProvider{
+		testProvider(t, "test-jobs", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			return []Job{{Title: "Go Developer"}}, nil
+		}),
+		testProvider(t, "test-empty", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			return []Job{}, nil
+		}),
+		testProvider(t, "test-nil", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			return nil, nil
+		}),
+		testProvider(t, "test-failing", func(ctx context.Context, skills []string, limit int) ([]Job, error) {
+			return nil, errors.New("status 500")
+		}),
+	}
+
+	jobs, failed := fetchParallel(context.Background(), providers, []string{"go"}, 10)
+	if len(jobs) != 1 || jobs[0].Source != "test-jobs" {
+		t.Errorf("jobs = %+v, want the one from test-jobs", jobs)
+	}
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// JobProvider is a source of job postings (a public job board API, an internal board, ...)
+type JobProvider interface {
+	// Name uniquely identifies the provider, e.g. "remoteok"
+	Name() string
+	// Priority is the tier the provider runs in; lower tiers are tried first
+	Priority() int
+	// Enabled reports whether the provider has the credentials it needs to run
+	Enabled() bool
+	// Fetch returns up to limit jobs matching the given skills. A search that matched
+	// nothing returns no jobs and a nil error, errors are reserved for failures.
+	Fetch(ctx context.Context, skills []string, limit int) ([]Job, error)
+}
+
+// Priority tiers used by the built-in providers
+const (
+	PriorityPrimary = 1
+	PriorityBackup  = 2
+)
+
+// FetchFunc is the signature of a provider fetch function
+type FetchFunc func(ctx context.Context, skills []string, limit int) ([]Job, error)
+
+// funcProvider adapts a plain fetch function to the JobProvider interface
+type funcProvider struct {
+	name     string
+	priority int
+	enabled  func() bool
+	fetch    FetchFunc
+}
+
+// NewJobProvider builds a JobProvider from a fetch function.
+// enabled may be nil for providers that need no credentials.
+func NewJobProvider(name string, priority int, enabled func() bool, fetch FetchFunc) JobProvider {
+	return &funcProvider{
+		name:     name,
+		priority: priority,
+		enabled:  enabled,
+		fetch:    fetch,
+	}
+}
+
+func (p *funcProvider) Name() string  { return p.name }
+func (p *funcProvider) Priority() int { return p.priority }
+
+func (p *funcProvider) Enabled() bool {
+	if p.enabled == nil {
+		return true
+	}
+	return p.enabled()
+}
+
+func (p *funcProvider) Fetch(ctx context.Context, skills []string, limit int) ([]Job, error) {
+	return p.fetch(ctx, skills, limit)
+}
+
+// providerRegistry holds all registered job providers
+type providerRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]JobProvider
+}
+
+var registry = &providerRegistry{providers: make(map[string]JobProvider)}
+
+// RegisterJobProvider adds a provider to the registry, replacing any provider with the same name
+func RegisterJobProvider(p JobProvider) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.providers[strings.ToLower(p.Name())] = p
+}
+
+// UnregisterJobProvider removes a provider from the registry
+func UnregisterJobProvider(name string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.providers, strings.ToLower(name))
+}
+
+// RegisteredJobProviders returns every registered provider sorted by priority then name
+func RegisteredJobProviders() []JobProvider {
+	registry.mu.RLock()
+	providers := make([]JobProvider, 0, len(registry.providers))
+	for _, p := range registry.providers {
+		providers = append(providers, p)
+	}
+	registry.mu.RUnlock()
+
+	sort.Slice(providers, func(i, j int) bool {
+		if providers[i].Priority() != providers[j].Priority() {
+			return providers[i].Priority() < providers[j].Priority()
+		}
+		return providers[i].Name() < providers[j].Name()
+	})
+	return providers
+}
+
+// ActiveJobProviders returns the providers allowed by config, grouped by priority tier (ascending)
+func ActiveJobProviders() [][]JobProvider {
+	var tiers [][]JobProvider
+	lastPriority := 0
+
+	for _, p := range RegisteredJobProviders() {
+		if !providerAllowed(p.Name()) || !p.Enabled() {
+			continue
+		}
+		if len(tiers) == 0 || p.Priority() != lastPriority {
+			tiers = append(tiers, nil)
+			lastPriority = p.Priority()
+		}
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], p)
+	}
+	return tiers
+}
+
+// providerAllowed checks a provider name against the JOB_PROVIDERS allow list
+// and the JOB_PROVIDERS_DISABLED deny list (both comma separated, case-insensitive)
+func providerAllowed(name string) bool {
+	name = strings.ToLower(name)
+
+	if disabled := envList("JOB_PROVIDERS_DISABLED"); contains(disabled, name) {
+		return false
+	}
+	if allowed := envList("JOB_PROVIDERS"); len(allowed) > 0 {
+		return contains(allowed, name)
+	}
+	return true
+}
+
+// envList splits a comma separated environment variable into trimmed, non-empty values
+func envList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// hasEnv reports whether all of the given environment variables are set
+func hasEnv(keys ...string) func() bool {
+	return func() bool {
+		for _, k := range keys {
+			if os.Getenv(k) == "" {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Register the built-in job board providers
+func init() {
+	// Priority 1: Fast, reliable APIs
+	RegisterJobProvider(NewJobProvider("remoteok", PriorityPrimary, nil, fetchFromRemoteOK))   // Free, no auth, tech jobs
+	RegisterJobProvider(NewJobProvider("arbeitnow", PriorityPrimary, nil, fetchFromArbeitnow)) // Free, no auth, EU + US jobs
+	RegisterJobProvider(NewJobProvider("themuse", PriorityPrimary, nil, fetchFromTheMuse))     // Free, no auth, curated jobs
+	RegisterJobProvider(NewJobProvider("adzuna", PriorityPrimary, hasEnv("ADZUNA_APP_ID", "ADZUNA_APP_KEY"), fetchFromAdzuna))
+
+	// Priority 2: Backup APIs
+	RegisterJobProvider(NewJobProvider("findwork", PriorityBackup, nil, fetchFromFindwork)) // Free, no auth, tech focus
+	RegisterJobProvider(NewJobProvider("jooble", PriorityBackup, hasEnv("JOOBLE_API_KEY"), fetchFromJooble))
+	RegisterJobProvider(NewJobProvider("jsearch", PriorityBackup, hasEnv("RAPIDAPI_KEY"), fetchFromJSearch))
+}
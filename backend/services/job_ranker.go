@@ -0,0 +1,188 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JobPreferences are optional user preferences used when ranking jobs
+type JobPreferences struct {
+	Locations []string // e.g. "Remote", "Berlin", "New York"
+	JobTypes  []string // e.g. "Full-time", "Contract"
+}
+
+// Score weights, they add up to 100
+const (
+	skillScoreWeight    = 60.0
+	recencyScoreWeight  = 20.0
+	locationScoreWeight = 10.0
+	jobTypeScoreWeight  = 10.0
+)
+
+// How much a skill hit counts depending on where it was found
+const (
+	titleHitWeight       = 1.0
+	tagHitWeight         = 0.8
+	descriptionHitWeight = 0.5
+)
+
+// maxRankedSkills caps how many skills a job needs to match for a full skill score
+const maxRankedSkills = 5
+
+// maxJobAgeDays is the age after which a posting gets no recency points
+const maxJobAgeDays = 30.0
+
+// rankJobs scores every job against the resume skills and preferences and
+// sorts them best first. Jobs with equal scores keep their original order.
+func rankJobs(jobs []Job, skills []string, prefs JobPreferences) []Job {
+	now := time.Now()
+	for i := range jobs {
+		scoreJob(&jobs[i], skills, prefs, now)
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].Score > jobs[j].Score
+	})
+	return jobs
+}
+
+// scoreJob fills in Score (0-100) and MatchedSkills for a single job
+func scoreJob(job *Job, skills []string, prefs JobPreferences, now time.Time) {
+	title := strings.ToLower(job.Title)
+	description := strings.ToLower(job.Description)
+	tags := make([]string, len(job.Tags))
+	for i, t := range job.Tags {
+		tags[i] = strings.ToLower(t)
+	}
+
+	// Skill relevance: best hit per skill, title > tags > description
+	matched := make([]string, 0)
+	hits := 0.0
+	for _, skill := range skills {
+		s := strings.ToLower(strings.TrimSpace(skill))
+		if s == "" {
+			continue
+		}
+
+		weight := 0.0
+		switch {
+		case containsWord(title, s):
+			weight = titleHitWeight
+		case anyContainsWord(tags, s):
+			weight = tagHitWeight
+		case containsWord(description, s):
+			weight = descriptionHitWeight
+		}
+
+		if weight > 0 {
+			hits += weight
+			matched = append(matched, skill)
+		}
+	}
+
+	skillScore := 0.0
+	if expected := min(len(skills), maxRankedSkills); expected > 0 {
+		skillScore = math.Min(hits/float64(expected), 1) * skillScoreWeight
+	}
+
+	score := skillScore +
+		recencyScore(job.PostedDate, now) +
+		preferenceScore(job.Location, prefs.Locations, locationScoreWeight) +
+		preferenceScore(job.JobType, prefs.JobTypes, jobTypeScoreWeight)
+
+	job.Score = math.Round(score*10) / 10
+	job.MatchedSkills = matched
+}
+
+// recencyScore gives full points to jobs posted today, decaying linearly to zero
+// at maxJobAgeDays. Jobs with an unknown date get a quarter of the points.
+func recencyScore(postedDate string, now time.Time) float64 {
+	posted, ok := parsePostedDate(postedDate)
+	if !ok {
+		return recencyScoreWeight / 4
+	}
+
+	ageDays := now.Sub(posted).Hours() / 24
+	if ageDays < 0 {
+		ageDays = 0
+	}
+	return math.Max(0, 1-ageDays/maxJobAgeDays) * recencyScoreWeight
+}
+
+// preferenceScore awards the weight when value matches any of the preferred values
+func preferenceScore(value string, preferred []string, weight float64) float64 {
+	value = strings.ToLower(value)
+	for _, p := range preferred {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" && strings.Contains(value, p) {
+			return weight
+		}
+	}
+	return 0
+}
+
+// parsePostedDate understands the date formats returned by the job providers
+func parsePostedDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+
+	layouts := []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04:05.000Z",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	// Unix timestamps
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil && secs > 0 {
+		return time.Unix(secs, 0), true
+	}
+	return time.Time{}, false
+}
+
+// containsWord reports whether word appears in text on word boundaries,
+// so "go" doesn't match "good" but "c++" and "node.js" still match.
+func containsWord(text, word string) bool {
+	for start := 0; start <= len(text)-len(word); {
+		idx := strings.Index(text[start:], word)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:idx])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (idx == 0 || !isWordChar(before)) && (end == len(text) || !isWordChar(after)) {
+			return true
+		}
+		start = idx + 1
+	}
+	return false
+}
+
+func anyContainsWord(texts []string, word string) bool {
+	for _, t := range texts {
+		if containsWord(t, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	pdf "github.com/ledongthuc/pdf"
+)
+
+// PDF text extraction modes (PDF_EXTRACT_MODE)
+const (
+	// PdfExtractLayout rebuilds lines and columns from glyph positions
+	PdfExtractLayout = "layout"
+	// PdfExtractPlain takes the text in content stream order, columns get interleaved
+	PdfExtractPlain = "plain"
+)
+
+// Layout thresholds, relative to the font size unless noted
+const (
+	layoutLineTolerance = 0.5  // max baseline difference of glyphs on one line
+	layoutWordGap       = 0.2  // gap that separates two words
+	layoutSegmentGap    = 2.0  // gap that splits a line into separate segments (columns, right aligned dates)
+	layoutSectionGap    = 2.0  // vertical gap between two lines that sets a section apart
+	layoutAlignSlack    = 2.0  // points, how far column starts may be off
+	layoutMinColumnRows = 3    // segments needed on both sides of a gutter
+	layoutGutterMargin  = 0.15 // share of the text width at both edges where no gutter is looked for
+)
+
+// errNoLayout is returned for pages whose fonts don't report glyph widths,
+// their positions can't be trusted and plain extraction is used instead
+var errNoLayout = errors.New("page has no usable glyph positions")
+
+// layoutSegment is a run of text on one line, separated from the rest of the line by a wide gap
+type layoutSegment struct {
+	x0, x1 float64
+	y      float64 // baseline
+	size   float64 // font size of the first glyph
+	line   int     // index of the line, top to bottom
+	text   string
+}
+
+// PdfExtractMode returns the configured PDF extraction mode, layout unless PDF_EXTRACT_MODE=plain
+func PdfExtractMode() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("PDF_EXTRACT_MODE")), PdfExtractPlain) {
+		return PdfExtractPlain
+	}
+	return PdfExtractLayout
+}
+
+// layoutPageText returns the text of a page in reading order: glyphs are grouped into
+// lines, the lines split into segments at wide gaps and the segments assigned to columns.
+// Columns are written one after another, full width lines (headers, footers) close them.
+func layoutPageText(page pdf.Page) (text string, err error) {
+	// The content parser panics on some malformed streams
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed page content: %v", r)
+		}
+	}()
+
+	segments, err := layoutSegments(page.Content().Text)
+	if err != nil {
+		return "", err
+	}
+	return layoutText(segments, findGutters(segments)), nil
+}
+
+// layoutSegments groups glyphs into lines (top to bottom) and splits each line at wide gaps
+func layoutSegments(glyphs []pdf.Text) ([]layoutSegment, error) {
+	var visible []pdf.Text
+	noWidth := 0
+	for _, g := range glyphs {
+		if strings.TrimSpace(g.S) == "" {
+			continue // word gaps are rebuilt from the positions
+		}
+		if g.W <= 0 {
+			noWidth++
+		}
+		visible = append(visible, g)
+	}
+	if len(visible) == 0 {
+		return nil, nil
+	}
+	if noWidth*2 > len(visible) {
+		return nil, errNoLayout
+	}
+
+	// PDF coordinates grow upwards
+	sort.SliceStable(visible, func(i, j int) bool { return visible[i].Y > visible[j].Y })
+
+	var segments []layoutSegment
+	line := 0
+	for start := 0; start < len(visible); line++ {
+		end := start + 1
+		for end < len(visible) && visible[start].Y-visible[end].Y <= layoutLineTolerance*fontSize(visible[start]) {
+			end++
+		}
+		glyphs := visible[start:end]
+		sort.SliceStable(glyphs, func(i, j int) bool { return glyphs[i].X < glyphs[j].X })
+		segments = append(segments, lineSegments(glyphs, line)...)
+		start = end
+	}
+	return segments, nil
+}
+
+// lineSegments joins the glyphs of one line, sorted left to right, into segments
+func lineSegments(glyphs []pdf.Text, line int) []layoutSegment {
+	var segments []layoutSegment
+	var text strings.Builder
+	var cur layoutSegment
+	var last pdf.Text
+
+	flush := func() {
+		cur.text = strings.TrimSpace(text.String())
+		if cur.text != "" {
+			segments = append(segments, cur)
+		}
+		text.Reset()
+	}
+
+	for i, g := range glyphs {
+		size := fontSize(g)
+		if i > 0 {
+			gap := g.X - cur.x1
+			switch {
+			case gap > layoutSegmentGap*size:
+				flush()
+			case g.S == last.S && math.Abs(g.X-last.X) < 0.1*size:
+				continue // drawn twice for a bold effect
+			case gap > layoutWordGap*size:
+				text.WriteByte(' ')
+			}
+		}
+		if text.Len() == 0 {
+			cur = layoutSegment{x0: g.X, x1: g.X, y: g.Y, size: size, line: line}
+		}
+		text.WriteString(g.S)
+		cur.x1 = math.Max(cur.x1, g.X+glyphWidth(g))
+		last = g
+	}
+	flush()
+	return segments
+}
+
+// findGutters returns the x positions where columns start, left to right. A column
+// start is an x where several segments begin, several segments end left of it
+// and few segments run across it. Each side is searched again for more columns.
+func findGutters(segments []layoutSegment) []float64 {
+	if len(segments) < 2*layoutMinColumnRows {
+		return nil
+	}
+	minX, maxX := segments[0].x0, segments[0].x1
+	for _, s := range segments {
+		minX = math.Min(minX, s.x0)
+		maxX = math.Max(maxX, s.x1)
+	}
+	margin := layoutGutterMargin * (maxX - minX)
+
+	best, bestStarting := 0.0, 0
+	for _, candidate := range segments {
+		c := candidate.x0
+		if c < minX+margin || c > maxX-margin {
+			continue
+		}
+		starting, left, crossing := 0, 0, 0
+		for _, s := range segments {
+			switch {
+			case math.Abs(s.x0-c) <= layoutAlignSlack:
+				starting++
+			case s.x1 <= c:
+				left++
+			case s.x0 < c:
+				crossing++
+			}
+		}
+		if starting >= layoutMinColumnRows && left >= layoutMinColumnRows &&
+			crossing*2 <= starting && starting > bestStarting {
+			best, bestStarting = c, starting
+		}
+	}
+	if bestStarting == 0 {
+		return nil
+	}
+
+	var left, right []layoutSegment
+	for _, s := range segments {
+		switch {
+		case s.x1 <= best:
+			left = append(left, s)
+		case s.x0 >= best-layoutAlignSlack:
+			right = append(right, s)
+		}
+	}
+	gutters := append(findGutters(left), best)
+	return append(gutters, findGutters(right)...)
+}
+
+// layoutText writes the segments in reading order. Lines that run across a gutter are
+// full width: they end the columns above them and are written as they are. A short
+// heading right above a full width line, below the end of the columns, belongs to it.
+func layoutText(segments []layoutSegment, gutters []float64) string {
+	var out strings.Builder
+	var block [][]layoutSegment // lines since the last full width line
+
+	writeLine := func(line []layoutSegment) {
+		for i, s := range line {
+			if i > 0 {
+				out.WriteByte(' ')
+			}
+			out.WriteString(s.text)
+		}
+		out.WriteByte('\n')
+	}
+	writeColumns := func(lines [][]layoutSegment) {
+		columns := make([][][]layoutSegment, len(gutters)+1)
+		for _, line := range lines {
+			for start := 0; start < len(line); {
+				column := columnOf(line[start], gutters)
+				end := start + 1
+				for end < len(line) && columnOf(line[end], gutters) == column {
+					end++
+				}
+				columns[column] = append(columns[column], line[start:end])
+				start = end
+			}
+		}
+		for _, column := range columns {
+			for _, line := range column {
+				writeLine(line)
+			}
+		}
+	}
+
+	for _, line := range layoutLines(segments) {
+		if !spansGutter(line, gutters) {
+			block = append(block, line)
+			continue
+		}
+		heading := sectionHeading(block, line, gutters)
+		writeColumns(block[:len(block)-len(heading)])
+		for _, l := range heading {
+			writeLine(l)
+		}
+		writeLine(line)
+		block = nil
+	}
+	writeColumns(block)
+	return out.String()
+}
+
+// layoutLines splits segments, sorted top to bottom and left to right, into lines
+func layoutLines(segments []layoutSegment) [][]layoutSegment {
+	var lines [][]layoutSegment
+	for start := 0; start < len(segments); {
+		end := start + 1
+		for end < len(segments) && segments[end].line == segments[start].line {
+			end++
+		}
+		lines = append(lines, segments[start:end])
+		start = end
+	}
+	return lines
+}
+
+// sectionHeading returns the lines at the end of block that head the full width line
+// below: at most two lines in one column, set apart from the rest of the block by a gap
+func sectionHeading(block [][]layoutSegment, fullWidth []layoutSegment, gutters []float64) [][]layoutSegment {
+	below := fullWidth[0].y
+	for n := 1; n <= 2 && n <= len(block); n++ {
+		line := block[len(block)-n]
+		if len(line) != 1 || columnOf(line[0], gutters) != columnOf(block[len(block)-1][0], gutters) {
+			return nil
+		}
+		if line[0].y-below > layoutSectionGap*line[0].size {
+			return nil
+		}
+		below = line[0].y
+		if n == len(block) || block[len(block)-n-1][0].y-below > layoutSectionGap*line[0].size {
+			return block[len(block)-n:]
+		}
+	}
+	return nil
+}
+
+// columnOf returns the index of the column a segment starts in
+func columnOf(s layoutSegment, gutters []float64) int {
+	column := 0
+	for column < len(gutters) && s.x0 >= gutters[column]-layoutAlignSlack {
+		column++
+	}
+	return column
+}
+
+// spansGutter reports whether a segment of the line runs into the next column
+func spansGutter(line []layoutSegment, gutters []float64) bool {
+	for _, s := range line {
+		if column := columnOf(s, gutters); column < len(gutters) && s.x1 > gutters[column]+layoutAlignSlack {
+			return true
+		}
+	}
+	return false
+}
+
+// fontSize returns the rendered size of a glyph, rotated or odd matrices can make it 0 or negative
+func fontSize(g pdf.Text) float64 {
+	if size := math.Abs(g.FontSize); size > 0 {
+		return size
+	}
+	return 10
+}
+
+// glyphWidth returns the advance of a glyph, estimated when the font has no widths
+func glyphWidth(g pdf.Text) float64 {
+	if g.W > 0 {
+		return g.W
+	}
+	return 0.5 * fontSize(g)
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pdf "github.com/ledongthuc/pdf"
+)
+
+// The fixtures in testdata are one page PDFs set in Courier with a /Widths array,
+// their content streams draw both columns row by row like most resume builders do.
+func TestExtractTextFromPdfFileLayout(t *testing.T) {
+	t.Setenv("PDF_EXTRACT_MODE", PdfExtractLayout)
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			// full width header, two equal columns, full width section below
+			file: "two_column.pdf",
+			want: []string{
+				"JANE DOE",
+				"jane@example.com | +1 555 0100 | Berlin",
+				"EXPERIENCE",
+				"Senior Engineer, Acme Corp",
+				"2020 - Present",
+				"- Built billing APIs in Go",
+				"- Led a team of four",
+				"Engineer, Globex",
+				"2017 - 2020",
+				"- Migrated services to AWS",
+				"SKILLS",
+				"Go, Python, SQL",
+				"Docker, Kubernetes",
+				"EDUCATION",
+				"BSc Computer Science",
+				"State University",
+				"2013 - 2017",
+				"PROJECTS",
+				"Resume parser - extracts sections from PDF resumes written in Go",
+			},
+		},
+		{
+			// narrow sidebar left of the main column, the columns have different line spacing
+			file: "sidebar.pdf",
+			want: []string{
+				"CONTACT",
+				"john@example.com",
+				"+44 20 7946",
+				"London",
+				"SKILLS",
+				"Java",
+				"Spring",
+				"PostgreSQL",
+				"JOHN SMITH",
+				"Backend developer with eight",
+				"years of experience.",
+				"EXPERIENCE",
+				"Developer, Initech 2019 - 2023",
+				"- Designed payment services",
+			},
+		},
+		{
+			// one column, right aligned dates stay on their heading line
+			file: "single_column.pdf",
+			want: []string{
+				"ALEX KIM",
+				"alex@example.com | Seattle, WA",
+				"EXPERIENCE",
+				"Data Engineer, Umbrella 2021 - Present",
+				"- Maintained Spark pipelines processing terabytes of events daily",
+				"- Cut warehouse costs by a third with partitioned Parquet tables",
+				"Analyst, Hooli 2018 - 2021",
+				"- Reported weekly growth metrics to the leadership team in SQL",
+				"SKILLS",
+				"Python, Spark, SQL, Airflow",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			text, err := ExtractTextFromPdfFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatalf("ExtractTextFromPdfFile: %v", err)
+			}
+			got := strings.Split(strings.TrimSpace(text), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("text in wrong order\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestExtractTextFromPdfFilePlainMode(t *testing.T) {
+	t.Setenv("PDF_EXTRACT_MODE", PdfExtractPlain)
+
+	text, err := ExtractTextFromPdfFile(filepath.Join("testdata", "two_column.pdf"))
+	if err != nil {
+		t.Fatalf("ExtractTextFromPdfFile: %v", err)
+	}
+	// content stream order interleaves the columns
+	if strings.Index(text, "SKILLS") > strings.Index(text, "Senior Engineer, Acme Corp") {
+		t.Errorf("plain mode should keep content stream order, got:\n%s", text)
+	}
+}
+
+func TestLayoutSegmentsWithoutWidths(t *testing.T) {
+	glyphs := []pdf.Text{
+		{FontSize: 10, X: 50, Y: 700, S: "G"},
+		{FontSize: 10, X: 50, Y: 700, S: "o"},
+	}
+	if _, err := layoutSegments(glyphs); err != errNoLayout {
+		t.Errorf("layoutSegments err = %v, want errNoLayout", err)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrResumeNotReady is returned when a resume is re-analyzed before its upload was processed
+var ErrResumeNotReady = errors.New("resume is still being processed or failed, it has no stored file to analyze")
+
+// ReanalyzeResume analyzes a processed resume against another job description and
+// stores the run as a new ResumeAnalysis. The resume itself keeps its upload analysis.
+// jobDescriptionId links the run to the saved job description the text came from, if any.
+func ReanalyzeResume(ctx context.Context, resume *models.Resume, jobDescription string, jobDescriptionId *uint) (*models.ResumeAnalysis, error) {
+	if resume.Status != models.ResumeStatusDone || resume.FileId == "" {
+		return nil, ErrResumeNotReady
+	}
+
+	text, err := ResumeText(ctx, resume)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := AnalyzeResumeText(ctx, text, jobDescription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze resume: %w", err)
+	}
+
+	record := newResumeAnalysis(resume.Id, jobDescription, jobDescriptionId, analysis)
+	if err := config.DB.Create(record).Error; err != nil {
+		return nil, err
+	}
+	fmt.Printf("🔁 Re-analyzed resume %d as analysis %d: JD match %d%%\n", resume.Id, record.Id, record.JdMatchScore)
+	return record, nil
+}
+
+// ResumeAnalyses returns the analysis runs of a resume, newest first
+func ResumeAnalyses(resumeId uint) ([]models.ResumeAnalysis, error) {
+	var analyses []models.ResumeAnalysis
+	err := config.DB.Where("resume_id = ?", resumeId).Order("created_at DESC, id DESC").Find(&analyses).Error
+	return analyses, err
+}
+
+// saveResumeAnalysis records the upload analysis of a resume as its first run
+func saveResumeAnalysis(resumeId uint, jobDescription string, jobDescriptionId *uint, analysis *AnalysisResult) error {
+	return config.DB.Create(newResumeAnalysis(resumeId, jobDescription, jobDescriptionId, analysis)).Error
+}
+
+func newResumeAnalysis(resumeId uint, jobDescription string, jobDescriptionId *uint, analysis *AnalysisResult) *models.ResumeAnalysis {
+	data, _ := json.Marshal(analysis)
+	matchingSkills, _ := json.Marshal(analysis.MatchingSkills)
+	missingSkills, _ := json.Marshal(analysis.MissingSkills)
+	return &models.ResumeAnalysis{
+		ResumeId:         resumeId,
+		JobDescription:   strings.TrimSpace(jobDescription),
+		JdHash:           HashJobDescription(jobDescription),
+		JobDescriptionId: jobDescriptionId,
+		Analyzer:         analysis.Analyzer,
+		AnalysisResult:   string(data),
+		AtsScore:         analysis.AtsScore,
+		JdMatchScore:     analysis.JdMatchScore,
+		MatchingSkills:   string(matchingSkills),
+		MissingSkills:    string(missingSkills),
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+)
+
+// HashFile returns the hex SHA-256 of a file's contents
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashJobDescription returns the hex SHA-256 of a job description, "" when there is none
+func HashJobDescription(jobDescription string) string {
+	jobDescription = strings.TrimSpace(jobDescription)
+	if jobDescription == "" {
+		return ""
+	}
+	return sha256Hex([]byte(jobDescription))
+}
+
+// FindDuplicateResume looks for an earlier upload of identical bytes by the same user.
+// same is a resume analyzed against the same job description that can be returned as is
+// (done or still processing). Otherwise source is an identical upload whose stored file,
+// and stored text if it has one, can be reused instead of uploading and extracting again.
+func FindDuplicateResume(userId uint, contentHash, jdHash string) (same *models.Resume, source *models.Resume) {
+	var matches []models.Resume
+	err := config.DB.
+		Where("user_id = ? AND content_hash = ? AND status <> ?", userId, contentHash, models.ResumeStatusFailed).
+		Order("uploaded_at DESC").
+		Find(&matches).Error
+	if err != nil {
+		return nil, nil
+	}
+
+	for i := range matches {
+		if matches[i].JdHash == jdHash {
+			return &matches[i], nil
+		}
+	}
+	for i := range matches {
+		if matches[i].FileId != "" {
+			return nil, &matches[i]
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"backend/models"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Resume event types sent on GET /api/resume/:id/events
+const (
+	EventStatus   = "status"   // overall status changed (pending)
+	EventStage    = "stage"    // a processing stage started, finished, failed or was skipped
+	EventAnalysis = "analysis" // analyzer result is available
+	EventJob      = "job"      // one job recommendation
+	EventDone     = "done"     // processing finished, last event
+	EventFailed   = "failed"   // processing failed, last event
+)
+
+// eventLogRetention is how long the events of a finished resume are kept for reconnecting clients
+const eventLogRetention = 10 * time.Minute
+
+// subscriberBuffer is how many events a slow subscriber may lag behind before it is dropped
+// (it can reconnect with Last-Event-ID and replay what it missed)
+const subscriberBuffer = 64
+
+// ResumeEvent is one progress event of a resume being processed
+type ResumeEvent struct {
+	Id   int         `json:"id"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// resumeEventLog keeps every event of one resume so reconnecting clients can replay them
+type resumeEventLog struct {
+	events      []ResumeEvent
+	subscribers map[chan ResumeEvent]struct{}
+	closed      bool
+}
+
+var (
+	eventsMu  sync.Mutex
+	eventLogs = make(map[uint]*resumeEventLog)
+)
+
+// openResumeEvents starts the event log of a resume
+func openResumeEvents(resumeId uint) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	eventLogs[resumeId] = &resumeEventLog{subscribers: make(map[chan ResumeEvent]struct{})}
+}
+
+// publishResumeEvent appends an event to the log of a resume and sends it to live subscribers.
+// done and failed events close the log.
+func publishResumeEvent(resumeId uint, eventType string, data interface{}) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	log, ok := eventLogs[resumeId]
+	if !ok || log.closed {
+		return
+	}
+
+	event := ResumeEvent{Id: len(log.events) + 1, Type: eventType, Data: data}
+	log.events = append(log.events, event)
+
+	for ch := range log.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// too slow, drop it, the client reconnects with Last-Event-ID
+			delete(log.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	if eventType == EventDone || eventType == EventFailed {
+		log.closed = true
+		for ch := range log.subscribers {
+			close(ch)
+		}
+		log.subscribers = nil
+
+		time.AfterFunc(eventLogRetention, func() {
+			eventsMu.Lock()
+			defer eventsMu.Unlock()
+			if eventLogs[resumeId] == log {
+				delete(eventLogs, resumeId)
+			}
+		})
+	}
+}
+
+// SubscribeResumeEvents returns the events after lastEventId and a channel of live events.
+// live is nil when processing already finished. ok is false when no event log exists
+// for the resume (processed before the server started or expired), callers should
+// then build the events from the database with ResumeSnapshotEvents.
+func SubscribeResumeEvents(resumeId uint, lastEventId int) (replay []ResumeEvent, live <-chan ResumeEvent, cancel func(), ok bool) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	log, exists := eventLogs[resumeId]
+	if !exists {
+		return nil, nil, func() {}, false
+	}
+
+	for _, event := range log.events {
+		if event.Id > lastEventId {
+			replay = append(replay, event)
+		}
+	}
+
+	if log.closed {
+		return replay, nil, func() {}, true
+	}
+
+	ch := make(chan ResumeEvent, subscriberBuffer)
+	log.subscribers[ch] = struct{}{}
+	cancel = func() {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		if _, subscribed := log.subscribers[ch]; subscribed {
+			delete(log.subscribers, ch)
+			close(ch)
+		}
+	}
+	return replay, ch, cancel, true
+}
+
+// ResumeSnapshotEvents rebuilds the events of a resume from what is stored in the database,
+// in the order and with the IDs the pipeline published them, and returns those after lastEventId.
+// jobs must be in the order they were saved. Synthetic jobs that weren't stored can't be
+// replayed, the IDs after them shift.
+func ResumeSnapshotEvents(resume models.Resume, jobs []models.JobRecommendation, lastEventId int) []ResumeEvent {
+	var events []ResumeEvent
+	id := 0
+	add := func(eventType string, data interface{}) {
+		id++
+		if id > lastEventId {
+			events = append(events, ResumeEvent{Id: id, Type: eventType, Data: data})
+		}
+	}
+
+	add(EventStatus, eventData{"status": models.ResumeStatusPending})
+
+	var stages []models.ResumeStage
+	json.Unmarshal([]byte(resume.Stages), &stages)
+	for _, stage := range stages {
+		if stage.Status == models.StageStatusPending {
+			continue
+		}
+		// Skipped stages are published once, the others when they start and when they end
+		if stage.StartedAt != nil {
+			running := stage
+			running.Status = models.StageStatusRunning
+			running.Error = ""
+			running.FinishedAt = nil
+			add(EventStage, running)
+		}
+		if stage.Status == models.StageStatusDone {
+			switch stage.Stage {
+			case models.StageAnalyzing:
+				add(EventAnalysis, analysisEventData(&resume))
+			case models.StageFetchingJobs:
+				for _, job := range jobs {
+					add(EventJob, job)
+				}
+			}
+		}
+		if stage.Status != models.StageStatusRunning {
+			add(EventStage, stage)
+		}
+	}
+
+	switch resume.Status {
+	case models.ResumeStatusDone:
+		add(EventDone, eventData{"status": resume.Status})
+	case models.ResumeStatusFailed:
+		add(EventFailed, eventData{"status": resume.Status, "error": resume.Error})
+	}
+	return events
+}
+
+// analysisEventData is the payload of an analysis event
+func analysisEventData(resume *models.Resume) eventData {
+	return eventData{
+		"ats_score":       resume.AtsScore,
+		"jd_match_score":  resume.JdMatchScore,
+		"matching_skills": rawJSON(resume.MatchingSkills, "[]"),
+		"missing_skills":  rawJSON(resume.MissingSkills, "[]"),
+		"analysis_result": rawJSON(resume.AnalysisResult, "{}"),
+		"sections":        rawJSON(resume.Sections, "{}"),
+		"ats_warnings":    ResumeAtsWarnings(resume),
+	}
+}
+
+// eventData is a JSON object event payload
+type eventData map[string]interface{}
+
+// rawJSON returns s as raw JSON, or fallback when s isn't valid JSON
+func rawJSON(s, fallback string) json.RawMessage {
+	if !json.Valid([]byte(s)) {
+		return json.RawMessage(fallback)
+	}
+	return json.RawMessage(s)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"backend/models"
+	"errors"
+	"testing"
+)
+
+// publishTestRun publishes the events of a processed resume the way the pipeline does
+func publishTestRun(t *testing.T, resume *models.Resume, jobs []models.JobRecommendation, fetchErr error) {
+	t.Helper()
+	dryRunDB(t, *resume)
+
+	openResumeEvents(resume.Id)
+	publishResumeEvent(resume.Id, EventStatus, eventData{"status": models.ResumeStatusPending})
+
+	run := newResumeRun(resume)
+	if err := run.begin(); err != nil {
+		t.Fatal(err)
+	}
+	run.stage(models.StageExtracting, func() error { return nil })
+	run.stage(models.StageAnalyzing, func() error {
+		publishResumeEvent(resume.Id, EventAnalysis, analysisEventData(resume))
+		return nil
+	})
+	run.skip(models.StageUploading, "identical file already stored")
+	run.stage(models.StageFetchingJobs, func() error {
+		if fetchErr != nil {
+			return fetchErr
+		}
+		for _, job := range jobs {
+			publishResumeEvent(resume.Id, EventJob, job)
+		}
+		return nil
+	})
+	run.finish()
+}
+
+// Rebuilt events get the IDs the live log gave them, so Last-Event-ID works across both
+func TestResumeSnapshotEventsMatchLiveLog(t *testing.T) {
+	jobs := []models.JobRecommendation{{Id: 1, ResumeId: 31, Title: "Go developer"}, {Id: 2, ResumeId: 31, Title: "SRE"}}
+
+	tests := []struct {
+		name     string
+		fetchErr error
+	}{
+		{name: "jobs fetched"},
+		{name: "job fetch failed", fetchErr: errors.New("no providers answered")},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume := models.Resume{
+				Id:     uint(31 + i),
+				Status: models.ResumeStatusPending,
+				Stages: InitialResumeStages(),
+			}
+			runJobs := jobs
+			if tt.fetchErr != nil {
+				runJobs = nil
+			}
+			publishTestRun(t, &resume, runJobs, tt.fetchErr)
+
+			published, _, cancel, ok := SubscribeResumeEvents(resume.Id, 0)
+			defer cancel()
+			if !ok {
+				t.Fatal("no live event log")
+			}
+
+			for _, lastEventId := range []int{0, 3, len(published) - 1, len(published)} {
+				snapshot := ResumeSnapshotEvents(resume, runJobs, lastEventId)
+				want := published[lastEventId:]
+				if len(snapshot) != len(want) {
+					t.Fatalf("after %d: %d snapshot events, want %d: %+v", lastEventId, len(snapshot), len(want), snapshot)
+				}
+				for j := range want {
+					if snapshot[j].Id != want[j].Id || snapshot[j].Type != want[j].Type {
+						t.Errorf("after %d: event %d is %d %s, want %d %s", lastEventId, j,
+							snapshot[j].Id, snapshot[j].Type, want[j].Id, want[j].Type)
+					}
+					if stage, ok := want[j].Data.(models.ResumeStage); ok {
+						got, _ := snapshot[j].Data.(models.ResumeStage)
+						if got.Stage != stage.Stage || got.Status != stage.Status {
+							t.Errorf("after %d: event %d is stage %s %s, want %s %s", lastEventId, want[j].Id,
+								got.Stage, got.Status, stage.Stage, stage.Status)
+						}
+					}
+				}
+			}
+		})
+	}
+}
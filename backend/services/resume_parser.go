@@ -0,0 +1,588 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParsedResume is resume text split into its sections, stored as JSON in Resume.Sections
+type ParsedResume struct {
+	Contact        ContactInfo       `json:"contact"`
+	Summary        string            `json:"summary"`
+	Experience     []ExperienceEntry `json:"experience"`
+	Education      []EducationEntry  `json:"education"`
+	Skills         []string          `json:"skills"`
+	Projects       []ProjectEntry    `json:"projects"`
+	Certifications []string          `json:"certifications"`
+}
+
+// ContactInfo is the contact block at the top of a resume
+type ContactInfo struct {
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Location string   `json:"location"`
+	Links    []string `json:"links"`
+}
+
+// ExperienceEntry is one position in the experience section
+type ExperienceEntry struct {
+	Title      string   `json:"title"`
+	Employer   string   `json:"employer"`
+	StartDate  string   `json:"start_date"`
+	EndDate    string   `json:"end_date"` // "Present" for current positions
+	Current    bool     `json:"current"`
+	Highlights []string `json:"highlights"`
+}
+
+// EducationEntry is one degree or school in the education section
+type EducationEntry struct {
+	Institution string   `json:"institution"`
+	Degree      string   `json:"degree"`
+	StartDate   string   `json:"start_date"`
+	EndDate     string   `json:"end_date"`
+	Details     []string `json:"details"`
+}
+
+// ProjectEntry is one project in the projects section
+type ProjectEntry struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Technologies []string `json:"technologies"`
+}
+
+// Resume sections
+const (
+	sectionHeader         = "header" // text before the first heading, holds the contact info
+	sectionSummary        = "summary"
+	sectionExperience     = "experience"
+	sectionEducation      = "education"
+	sectionSkills         = "skills"
+	sectionProjects       = "projects"
+	sectionCertifications = "certifications"
+	sectionOther          = "other" // headings we don't parse (languages, interests, ...)
+)
+
+// sectionHeadings maps normalized heading lines to their section
+var sectionHeadings = map[string]string{
+	"summary":                     sectionSummary,
+	"professional summary":        sectionSummary,
+	"career summary":              sectionSummary,
+	"profile":                     sectionSummary,
+	"professional profile":        sectionSummary,
+	"about":                       sectionSummary,
+	"about me":                    sectionSummary,
+	"objective":                   sectionSummary,
+	"career objective":            sectionSummary,
+	"experience":                  sectionExperience,
+	"work experience":             sectionExperience,
+	"professional experience":     sectionExperience,
+	"relevant experience":         sectionExperience,
+	"employment":                  sectionExperience,
+	"employment history":          sectionExperience,
+	"work history":                sectionExperience,
+	"career history":              sectionExperience,
+	"internships":                 sectionExperience,
+	"education":                   sectionEducation,
+	"academic background":         sectionEducation,
+	"education and training":      sectionEducation,
+	"skills":                      sectionSkills,
+	"technical skills":            sectionSkills,
+	"key skills":                  sectionSkills,
+	"core skills":                 sectionSkills,
+	"core competencies":           sectionSkills,
+	"competencies":                sectionSkills,
+	"technologies":                sectionSkills,
+	"tech stack":                  sectionSkills,
+	"tools and technologies":      sectionSkills,
+	"skills and tools":            sectionSkills,
+	"projects":                    sectionProjects,
+	"personal projects":           sectionProjects,
+	"selected projects":           sectionProjects,
+	"academic projects":           sectionProjects,
+	"side projects":               sectionProjects,
+	"certifications":              sectionCertifications,
+	"certificates":                sectionCertifications,
+	"licenses and certifications": sectionCertifications,
+	"certifications and licenses": sectionCertifications,
+	"courses and certifications":  sectionCertifications,
+	"languages":                   sectionOther,
+	"interests":                   sectionOther,
+	"hobbies":                     sectionOther,
+	"awards":                      sectionOther,
+	"achievements":                sectionOther,
+	"publications":                sectionOther,
+	"volunteering":                sectionOther,
+	"volunteer experience":        sectionOther,
+	"references":                  sectionOther,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().\-]{7,}\d`)
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.|linkedin\.com/|github\.com/|gitlab\.com/)[^\s|,;]+`)
+	// "Jan 2020", "January 2020", "01/2020", "2020"
+	datePattern = `(?:(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+\d{4}|\d{1,2}/\d{4}|\d{4})`
+	// "Jan 2020 - Present", "2018 – 2021", "03/2019 to 05/2020"
+	dateRangePattern  = regexp.MustCompile(`(?i)(` + datePattern + `)\s*(?:-|–|—|to|until)\s*(` + datePattern + `|present|current|now|today|ongoing)`)
+	singleDatePattern = regexp.MustCompile(`(?i)\b` + datePattern + `\b`)
+	bulletPrefix      = regexp.MustCompile(`^(?:[•●▪■◦‣∙·*\-–—>]|\d{1,2}[.)])\s*`)
+	// "Technologies: Go, React" inside project descriptions
+	techPrefix = regexp.MustCompile(`(?i)^(?:technologies|tech stack|stack|built with|tools)\s*(?:used)?\s*:\s*`)
+)
+
+// titleWords mark a phrase as a job title rather than an employer
+var titleWords = []string{
+	"engineer", "developer", "programmer", "manager", "intern", "analyst", "designer", "lead",
+	"consultant", "scientist", "architect", "specialist", "director", "administrator", "officer",
+	"associate", "coordinator", "head of", "founder", "cto", "ceo", "vp", "president", "assistant",
+	"technician", "researcher", "tester", "devops", "sre", "owner", "executive", "representative",
+	"accountant", "teacher", "instructor", "nurse", "advisor", "strategist", "writer", "editor",
+}
+
+// degreeWords mark a phrase as a degree
+var degreeWords = []string{
+	"bachelor", "master", "b.sc", "bsc", "m.sc", "msc", "b.s.", "m.s.", "b.a.", "m.a.", "ph.d", "phd",
+	"b.tech", "btech", "m.tech", "mtech", "b.e.", "m.e.", "mba", "bba", "diploma", "associate degree",
+	"degree", "doctorate", "high school", "a-levels", "certificate in",
+}
+
+// institutionWords mark a phrase as a school
+var institutionWords = []string{
+	"university", "college", "institute", "school", "academy", "polytechnic", "universität", "hochschule", "iit", "mit",
+}
+
+// ParseResumeSections splits resume text into contact info, summary, experience,
+// education, skills, projects and certifications. Sections are found by their
+// headings; everything is best effort and missing sections stay empty.
+func ParseResumeSections(text string) ParsedResume {
+	sections := splitSections(text)
+
+	parsed := ParsedResume{
+		Contact:        parseContact(sections[sectionHeader], text),
+		Summary:        strings.Join(stripBullets(sections[sectionSummary]), " "),
+		Experience:     parseExperience(sections[sectionExperience]),
+		Education:      parseEducation(sections[sectionEducation]),
+		Skills:         parseSkillList(sections[sectionSkills]),
+		Projects:       parseProjects(sections[sectionProjects]),
+		Certifications: stripBullets(sections[sectionCertifications]),
+	}
+
+	// Keep arrays as [] rather than null in the stored JSON
+	if parsed.Contact.Links == nil {
+		parsed.Contact.Links = []string{}
+	}
+	if parsed.Experience == nil {
+		parsed.Experience = []ExperienceEntry{}
+	}
+	if parsed.Education == nil {
+		parsed.Education = []EducationEntry{}
+	}
+	if parsed.Skills == nil {
+		parsed.Skills = []string{}
+	}
+	if parsed.Projects == nil {
+		parsed.Projects = []ProjectEntry{}
+	}
+	if parsed.Certifications == nil {
+		parsed.Certifications = []string{}
+	}
+	return parsed
+}
+
+// splitSections groups the non-empty lines of the text by the heading above them
+func splitSections(text string) map[string][]string {
+	sections := make(map[string][]string)
+	current := sectionHeader
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if section, ok := headingSection(line); ok {
+			current = section
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+	return sections
+}
+
+// headingSection reports whether a line is a section heading and which one,
+// ignoring case, Markdown markup and trailing colons
+func headingSection(line string) (string, bool) {
+	if utf8.RuneCountInString(line) > 40 {
+		return "", false
+	}
+	h := strings.ToLower(strings.Trim(line, "#*_=:- \t"))
+	h = strings.ReplaceAll(h, "&", "and")
+	h = strings.Join(strings.Fields(h), " ")
+	section, ok := sectionHeadings[h]
+	return section, ok
+}
+
+// parseContact finds name, email, phone, location and links in the header lines,
+// falling back to the whole text for email and phone
+func parseContact(header []string, text string) ContactInfo {
+	var contact ContactInfo
+	headerText := strings.Join(header, "\n")
+
+	contact.Email = emailPattern.FindString(headerText)
+	if contact.Email == "" {
+		contact.Email = emailPattern.FindString(text)
+	}
+	contact.Phone = findPhone(headerText)
+	if contact.Phone == "" {
+		contact.Phone = findPhone(text)
+	}
+
+	seen := make(map[string]bool)
+	for _, link := range linkPattern.FindAllString(headerText, -1) {
+		link = strings.TrimRight(link, ".)")
+		if !seen[strings.ToLower(link)] {
+			seen[strings.ToLower(link)] = true
+			contact.Links = append(contact.Links, link)
+		}
+	}
+
+	for _, line := range header {
+		for _, part := range splitContactLine(line) {
+			switch {
+			case emailPattern.MatchString(part), linkPattern.MatchString(part), findPhone(part) != "":
+				continue
+			case contact.Name == "" && looksLikeName(part):
+				contact.Name = part
+			case contact.Location == "" && looksLikeLocation(part):
+				contact.Location = part
+			}
+		}
+	}
+	return contact
+}
+
+// findPhone returns the first phone number, ignoring year ranges like "2019 - 2021"
+func findPhone(text string) string {
+	for _, candidate := range phonePattern.FindAllString(text, -1) {
+		digits := 0
+		for _, r := range candidate {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if digits >= 8 && digits <= 15 && !dateRangePattern.MatchString(candidate) {
+			return strings.TrimSpace(candidate)
+		}
+	}
+	return ""
+}
+
+// splitContactLine splits "Jane Doe | jane@x.com | Berlin, Germany" into its parts
+func splitContactLine(line string) []string {
+	parts := strings.FieldsFunc(line, func(r rune) bool {
+		return strings.ContainsRune("|•·●▪\t", r)
+	})
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// looksLikeName reports whether a header part is a person's name: 2-4 capitalized words of letters
+func looksLikeName(s string) bool {
+	words := strings.Fields(s)
+	if len(words) < 2 || len(words) > 4 {
+		return false
+	}
+	for _, w := range words {
+		first, _ := utf8.DecodeRuneInString(w)
+		if !unicode.IsUpper(first) {
+			return false
+		}
+		for _, r := range w {
+			if !unicode.IsLetter(r) && r != '.' && r != '-' && r != '\'' {
+				return false
+			}
+		}
+	}
+	return !hasAnyWord(s, titleWords)
+}
+
+// looksLikeLocation reports whether a header part is a place like "Berlin, Germany" or "Austin, TX"
+func looksLikeLocation(s string) bool {
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 || len(parts) > 3 || utf8.RuneCountInString(s) > 60 {
+		return false
+	}
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		first, _ := utf8.DecodeRuneInString(p)
+		if p == "" || !unicode.IsUpper(first) {
+			return false
+		}
+		for _, r := range p {
+			if unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// entryLines groups section lines into entries: each entry starts with one or more
+// heading lines (title, employer, dates) followed by bullets or description lines.
+// A short non-bullet line after an entry's description, or a second date range,
+// starts the next entry.
+func entryLines(lines []string) [][]string {
+	var entries [][]string
+	var current []string
+	hasBody, hasDates := false, false
+
+	for _, line := range lines {
+		isBullet := bulletPrefix.MatchString(line) || techPrefix.MatchString(line)
+		isDates := dateRangePattern.MatchString(line)
+		isShort := len(strings.Fields(line)) <= 10 || isDates
+
+		startsEntry := len(current) == 0 ||
+			(!isBullet && isShort && hasBody) ||
+			(!isBullet && isDates && hasDates)
+		if startsEntry && len(current) > 0 {
+			entries = append(entries, current)
+			current, hasBody, hasDates = nil, false, false
+		}
+
+		current = append(current, line)
+		if isBullet || !isShort {
+			hasBody = true
+		}
+		if isDates {
+			hasDates = true
+		}
+	}
+	if len(current) > 0 {
+		entries = append(entries, current)
+	}
+	return entries
+}
+
+// splitEntry separates the heading lines of an entry from its body and pulls out the dates.
+// splitAt also splits headings on " at " ("Engineer at Acme"), which school names can't take.
+func splitEntry(lines []string, splitAt bool) (heading []string, body []string, start, end string) {
+	for i, line := range lines {
+		isDates := dateRangePattern.MatchString(line)
+		if bulletPrefix.MatchString(line) || (i > 0 && len(strings.Fields(line)) > 10 && !isDates) {
+			body = stripBullets(lines[i:])
+			break
+		}
+		if m := dateRangePattern.FindStringSubmatchIndex(line); m != nil && start == "" {
+			start, end = line[m[2]:m[3]], line[m[4]:m[5]]
+			line = line[:m[0]] + line[m[1]:]
+		} else if start == "" && end == "" {
+			if loc := singleDatePattern.FindStringIndex(line); loc != nil && len(strings.Fields(line)) <= 6 {
+				end = line[loc[0]:loc[1]]
+				line = line[:loc[0]] + line[loc[1]:]
+			}
+		}
+		heading = append(heading, headingParts(line, splitAt)...)
+	}
+	return heading, body, normalizeDate(start), normalizeDate(end)
+}
+
+// headingParts splits an entry heading like "Engineer at Acme — Berlin" into its parts
+func headingParts(line string, splitAt bool) []string {
+	if splitAt {
+		line = strings.ReplaceAll(line, " at ", " | ")
+	}
+	line = strings.ReplaceAll(line, " @ ", " | ")
+	parts := strings.FieldsFunc(line, func(r rune) bool {
+		return strings.ContainsRune("|–—•·,()\t", r)
+	})
+	var out []string
+	for _, p := range parts {
+		p = strings.Trim(strings.TrimSpace(p), "-:")
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// normalizeDate capitalizes "present" style end dates
+func normalizeDate(d string) string {
+	switch strings.ToLower(d) {
+	case "present", "current", "now", "today", "ongoing":
+		return "Present"
+	}
+	return d
+}
+
+// parseExperience parses the experience section into positions
+func parseExperience(lines []string) []ExperienceEntry {
+	var entries []ExperienceEntry
+	for _, lines := range entryLines(lines) {
+		heading, body, start, end := splitEntry(lines, true)
+		entry := ExperienceEntry{
+			StartDate:  start,
+			EndDate:    end,
+			Current:    end == "Present",
+			Highlights: body,
+		}
+		for _, part := range heading {
+			switch {
+			case entry.Title == "" && hasAnyWord(part, titleWords):
+				entry.Title = part
+			case entry.Employer == "" && !looksLikeLocation(part):
+				entry.Employer = part
+			}
+		}
+		// No title word anywhere: the first heading part is the title
+		if entry.Title == "" && entry.Employer != "" && len(heading) > 1 {
+			entry.Title, entry.Employer = entry.Employer, heading[1]
+		}
+		if entry.Highlights == nil {
+			entry.Highlights = []string{}
+		}
+		if entry.Title != "" || entry.Employer != "" || len(body) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseEducation parses the education section into degrees
+func parseEducation(lines []string) []EducationEntry {
+	var entries []EducationEntry
+	for _, lines := range entryLines(lines) {
+		heading, body, start, end := splitEntry(lines, false)
+		entry := EducationEntry{StartDate: start, EndDate: end}
+		var rest []string
+		for _, part := range heading {
+			switch {
+			case entry.Degree == "" && hasAnyWord(part, degreeWords):
+				entry.Degree = part
+			case entry.Institution == "" && hasAnyWord(part, institutionWords):
+				entry.Institution = part
+			default:
+				rest = append(rest, part)
+			}
+		}
+		if entry.Institution == "" && len(rest) > 0 && !looksLikeLocation(rest[0]) {
+			entry.Institution, rest = rest[0], rest[1:]
+		}
+		// Leftover heading parts (GPA, honors, location) are details too
+		entry.Details = append([]string{}, rest...)
+		entry.Details = append(entry.Details, body...)
+		if entry.Institution != "" || entry.Degree != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseProjects parses the projects section into projects
+func parseProjects(lines []string) []ProjectEntry {
+	var entries []ProjectEntry
+	for _, lines := range entryLines(lines) {
+		entry := ProjectEntry{Name: strings.TrimSpace(bulletPrefix.ReplaceAllString(lines[0], ""))}
+		// "Name | Go, React" or "Name – short description" on the first line
+		if i := strings.IndexAny(entry.Name, "|–—"); i > 0 {
+			rest := strings.TrimSpace(strings.TrimLeft(entry.Name[i:], "|–— "))
+			entry.Name = strings.TrimSpace(entry.Name[:i])
+			if isTechList(rest) {
+				entry.Technologies = splitSkills(rest)
+			} else if rest != "" {
+				lines = append([]string{entry.Name, rest}, lines[1:]...)
+			}
+		}
+
+		var description []string
+		for _, line := range stripBullets(lines[1:]) {
+			if techPrefix.MatchString(line) {
+				entry.Technologies = append(entry.Technologies, splitSkills(techPrefix.ReplaceAllString(line, ""))...)
+				continue
+			}
+			description = append(description, line)
+		}
+		entry.Description = strings.Join(description, " ")
+		if entry.Technologies == nil {
+			entry.Technologies = []string{}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// isTechList reports whether s looks like "Go, React, PostgreSQL" rather than a sentence
+func isTechList(s string) bool {
+	items := splitSkills(s)
+	if len(items) < 2 {
+		return false
+	}
+	for _, item := range items {
+		if len(strings.Fields(item)) > 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSkillList flattens the skills section into a deduplicated list,
+// dropping category labels like "Languages: Go, Python"
+func parseSkillList(lines []string) []string {
+	var skills []string
+	seen := make(map[string]bool)
+	for _, line := range stripBullets(lines) {
+		if i := strings.Index(line, ":"); i > 0 && i < 40 {
+			line = line[i+1:]
+		}
+		for _, skill := range splitSkills(line) {
+			key := strings.ToLower(skill)
+			if !seen[key] {
+				seen[key] = true
+				skills = append(skills, skill)
+			}
+		}
+	}
+	return skills
+}
+
+// splitSkills splits a list of skills on commas, semicolons, pipes and bullets
+func splitSkills(s string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(",;|•·●▪\t", r)
+	})
+	var skills []string
+	for _, p := range parts {
+		p = strings.Trim(strings.TrimSpace(p), ".")
+		if p != "" && utf8.RuneCountInString(p) <= 40 {
+			skills = append(skills, p)
+		}
+	}
+	return skills
+}
+
+// stripBullets removes list markers from lines
+func stripBullets(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(bulletPrefix.ReplaceAllString(line, "")); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// hasAnyWord reports whether s contains any of the words, on word boundaries
+func hasAnyWord(s string, words []string) bool {
+	lower := strings.ToLower(s)
+	for _, w := range words {
+		if containsWord(lower, w) {
+			return true
+		}
+	}
+	return false
+}
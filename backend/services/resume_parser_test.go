@@ -0,0 +1,265 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseResumeContact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want ContactInfo
+	}{
+		{
+			name: "one line per field",
+			text: "Jane Doe\njane.doe@example.com\n+1 (555) 123-4567\nAustin, TX\n\nSkills\nGo",
+			want: ContactInfo{Name: "Jane Doe", Email: "jane.doe@example.com", Phone: "+1 (555) 123-4567", Location: "Austin, TX", Links: []string{}},
+		},
+		{
+			name: "pipe separated with links",
+			text: "Jane Doe | jane@x.io | Berlin, Germany\nlinkedin.com/in/janedoe | https://github.com/janedoe.\n\nExperience",
+			want: ContactInfo{Name: "Jane Doe", Email: "jane@x.io", Location: "Berlin, Germany", Links: []string{"linkedin.com/in/janedoe", "https://github.com/janedoe"}},
+		},
+		{
+			name: "job title is not a name",
+			text: "Senior Backend Engineer\nJane Doe",
+			want: ContactInfo{Name: "Jane Doe", Links: []string{}},
+		},
+		{
+			name: "year range is not a phone",
+			text: "Jane Doe\n\nExperience\nEngineer at Acme 2019 - 2021",
+			want: ContactInfo{Name: "Jane Doe", Links: []string{}},
+		},
+		{
+			name: "email outside the header",
+			text: "Jane Doe\n\nReferences\nAsk me at jane@x.io",
+			want: ContactInfo{Name: "Jane Doe", Email: "jane@x.io", Links: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseResumeSections(tt.text).Contact; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contact = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeExperience(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []ExperienceEntry
+	}{
+		{
+			name: "title, employer and dates on one line",
+			text: "Senior Software Engineer | Acme Corp | Jan 2020 – Present\n• Built the billing service",
+			want: []ExperienceEntry{{Title: "Senior Software Engineer", Employer: "Acme Corp", StartDate: "Jan 2020", EndDate: "Present", Current: true, Highlights: []string{"Built the billing service"}}},
+		},
+		{
+			name: "title at employer, dates below",
+			text: "Software Developer at Initech\n03/2017 - 12/2019\n- Maintained the REST API",
+			want: []ExperienceEntry{{Title: "Software Developer", Employer: "Initech", StartDate: "03/2017", EndDate: "12/2019", Highlights: []string{"Maintained the REST API"}}},
+		},
+		{
+			name: "employer first, location skipped",
+			text: "Acme Corp, Berlin, Germany\nBackend Engineer\nSeptember 2018 to current",
+			want: []ExperienceEntry{{Title: "Backend Engineer", Employer: "Acme Corp", StartDate: "September 2018", EndDate: "Present", Current: true, Highlights: []string{}}},
+		},
+		{
+			name: "years only, en dash",
+			text: "Data Analyst — Globex\n2015 – 2018",
+			want: []ExperienceEntry{{Title: "Data Analyst", Employer: "Globex", StartDate: "2015", EndDate: "2018", Highlights: []string{}}},
+		},
+		{
+			name: "two positions",
+			text: "Lead Developer | Acme | 2020 - now\n- Led a team of 4\nDeveloper | Initech | 2017 - 2020\n- Wrote Go",
+			want: []ExperienceEntry{
+				{Title: "Lead Developer", Employer: "Acme", StartDate: "2020", EndDate: "Present", Current: true, Highlights: []string{"Led a team of 4"}},
+				{Title: "Developer", Employer: "Initech", StartDate: "2017", EndDate: "2020", Highlights: []string{"Wrote Go"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseResumeSections("Experience\n" + tt.text).Experience
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("experience = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeEducation(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []EducationEntry
+	}{
+		{
+			name: "degree, school and dates on one line",
+			text: "B.Sc. Computer Science, University of Texas, 2013 - 2017\nGPA 3.8",
+			want: []EducationEntry{{Institution: "University of Texas", Degree: "B.Sc. Computer Science", StartDate: "2013", EndDate: "2017", Details: []string{"GPA 3.8"}}},
+		},
+		{
+			name: "school first, graduation year",
+			text: "Technische Universität München\nMaster of Science in Informatics\n2019",
+			want: []EducationEntry{{Institution: "Technische Universität München", Degree: "Master of Science in Informatics", EndDate: "2019", Details: []string{}}},
+		},
+		{
+			name: "school without school words",
+			text: "Stanford | MBA | 2010 – 2012",
+			want: []EducationEntry{{Institution: "Stanford", Degree: "MBA", StartDate: "2010", EndDate: "2012", Details: []string{}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseResumeSections("Education\n" + tt.text).Education
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("education = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeSkills(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"comma separated", "Go, Python, SQL", []string{"Go", "Python", "SQL"}},
+		{"bullets", "• Go\n• Python\n- SQL", []string{"Go", "Python", "SQL"}},
+		{"category labels", "Languages: Go, Python\nTools: Docker; Kubernetes | PostgreSQL", []string{"Go", "Python", "Docker", "Kubernetes", "PostgreSQL"}},
+		{"duplicates", "Go, go, GO, SQL.", []string{"Go", "SQL"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseResumeSections("Skills\n" + tt.text).Skills; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("skills = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeProjects(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []ProjectEntry
+	}{
+		{
+			name: "technologies after the name",
+			text: "resumatch | Go, React\n- Matches resumes to jobs\nTechnologies: PostgreSQL",
+			want: []ProjectEntry{{Name: "resumatch", Description: "Matches resumes to jobs", Technologies: []string{"Go", "React", "PostgreSQL"}}},
+		},
+		{
+			name: "description after the name",
+			text: "Budget App – tracks shared expenses for households\nBuilt with: Flutter, Firebase",
+			want: []ProjectEntry{{Name: "Budget App", Description: "tracks shared expenses for households", Technologies: []string{"Flutter", "Firebase"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseResumeSections("Projects\n" + tt.text).Projects; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("projects = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeCertifications(t *testing.T) {
+	text := "Jane Doe\n\nLicenses & Certifications\n- AWS Certified Developer – Associate\n2. CKA\n\nInterests\nChess"
+	want := []string{"AWS Certified Developer – Associate", "CKA"}
+	if got := ParseResumeSections(text).Certifications; !reflect.DeepEqual(got, want) {
+		t.Errorf("certifications = %q, want %q", got, want)
+	}
+}
+
+// The same skills section under every heading style resumes use
+func TestParseResumeHeadingStyles(t *testing.T) {
+	tests := []string{
+		"Skills",
+		"SKILLS",
+		"Skills:",
+		"## Skills",
+		"**Technical Skills**",
+		"__Core Competencies__",
+		"  Skills & Tools  ",
+		"=== TECH STACK ===",
+		"--- Skills ---",
+	}
+	for _, heading := range tests {
+		t.Run(heading, func(t *testing.T) {
+			parsed := ParseResumeSections("Jane Doe\n" + heading + "\nGo, SQL")
+			if want := []string{"Go", "SQL"}; !reflect.DeepEqual(parsed.Skills, want) {
+				t.Errorf("skills = %q, want %q", parsed.Skills, want)
+			}
+			if parsed.Contact.Name != "Jane Doe" {
+				t.Errorf("name = %q, the heading leaked into the header", parsed.Contact.Name)
+			}
+		})
+	}
+}
+
+func TestParseResumeNotHeadings(t *testing.T) {
+	tests := []string{
+		"Skills in Go and SQL",
+		"Experience with distributed systems and a long history of shipping",
+	}
+	for _, line := range tests {
+		t.Run(line, func(t *testing.T) {
+			if section, ok := headingSection(line); ok {
+				t.Errorf("headingSection(%q) = %q, want no heading", line, section)
+			}
+		})
+	}
+}
+
+// Without headings everything is header: contact info is found, sections stay empty, not null
+func TestParseResumeWithoutHeadings(t *testing.T) {
+	parsed := ParseResumeSections("Jane Doe\njane@x.io\nI write Go services and like databases.\nAustin, TX")
+
+	want := ParsedResume{
+		Contact:        ContactInfo{Name: "Jane Doe", Email: "jane@x.io", Location: "Austin, TX", Links: []string{}},
+		Experience:     []ExperienceEntry{},
+		Education:      []EducationEntry{},
+		Skills:         []string{},
+		Projects:       []ProjectEntry{},
+		Certifications: []string{},
+	}
+	if !reflect.DeepEqual(parsed, want) {
+		t.Errorf("parsed = %+v, want %+v", parsed, want)
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		maxBytes int
+		want     string
+	}{
+		{"shorter", "résumé", 20, "résumé"},
+		{"multibyte exactly at the limit", "résumé", len("résumé"), "résumé"},
+		{"cut inside a rune", "résumé", len("résumé") - 1, "résum"},
+		{"cut after a rune", "résumé", 3, "ré"},
+		{"cut inside the first rune", "履歴書", 2, ""},
+		{"cjk at a rune boundary", "履歴書", 6, "履歴"},
+		{"ascii", "resume", 3, "res"},
+		{"zero", "résumé", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateUTF8(tt.in, tt.maxBytes)
+			if got != tt.want {
+				t.Errorf("TruncateUTF8(%q, %d) = %q, want %q", tt.in, tt.maxBytes, got, tt.want)
+			}
+			if !strings.HasPrefix(tt.in, got) || len(got) > tt.maxBytes && got != tt.in {
+				t.Errorf("TruncateUTF8(%q, %d) = %q is not a prefix within the limit", tt.in, tt.maxBytes, got)
+			}
+		})
+	}
+}
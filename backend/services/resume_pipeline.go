@@ -0,0 +1,559 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// Defaults, overridable with RESUME_WORKERS, RESUME_QUEUE_SIZE and RESUME_STALE_AFTER
+const (
+	defaultResumeWorkers    = 4
+	defaultResumeQueueSize  = 100
+	defaultResumeStaleAfter = 15 * time.Minute
+	recommendedJobsLimit    = 8
+)
+
+// ErrPipelineBusy is returned when the processing queue is full
+var ErrPipelineBusy = errors.New("resume processing queue is full")
+
+// errResumeDeleted stops processing of a resume deleted while it was being processed
+var errResumeDeleted = errors.New("resume was deleted during processing")
+
+// ResumeTask is an uploaded resume waiting to be processed in the background
+type ResumeTask struct {
+	ResumeId       uint
+	FilePath       string // temp file, removed once processed
+	TextFrom       uint   // resume with identical bytes whose stored text is reused, 0 extracts the file
+	JobDescription string
+	// JobDescriptionId is the saved job description JobDescription came from, nil for raw text
+	JobDescriptionId *uint
+	JobPrefs         JobPreferences
+}
+
+// resumePipeline runs resume tasks on a fixed pool of workers
+type resumePipeline struct {
+	ctx   context.Context
+	tasks chan ResumeTask
+}
+
+var pipeline *resumePipeline
+
+// StartResumePipeline starts the background workers. They stop when ctx is cancelled.
+func StartResumePipeline(ctx context.Context) {
+	workers := envInt("RESUME_WORKERS", defaultResumeWorkers)
+	queueSize := envInt("RESUME_QUEUE_SIZE", defaultResumeQueueSize)
+
+	pipeline = &resumePipeline{ctx: ctx, tasks: make(chan ResumeTask, queueSize)}
+	for i := 0; i < workers; i++ {
+		go pipeline.worker()
+	}
+	fmt.Printf("⚙️  Resume pipeline started: %d workers, queue size %d\n", workers, queueSize)
+
+	startStaleResumeRecovery(ctx)
+}
+
+// startStaleResumeRecovery fails resumes stuck pending or processing, at startup and then
+// periodically. Queued tasks live in the memory of the instance that accepted the upload,
+// so a resume nobody touched for RESUME_STALE_AFTER (default 15m, 0 disables) was lost
+// with a restarted or crashed instance. With several instances sharing the database the
+// cutoff must stay above the longest queue wait plus processing time, or another
+// instance's resumes are failed while they wait.
+func startStaleResumeRecovery(ctx context.Context) {
+	staleAfter := envDuration(defaultResumeStaleAfter, "RESUME_STALE_AFTER")
+	if staleAfter == 0 {
+		fmt.Println("⚠️  Stale resume recovery disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(staleAfter / 2)
+		defer ticker.Stop()
+		for {
+			recoverInterruptedResumes(time.Now().Add(-staleAfter))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// EnqueueResume queues a resume for processing without blocking
+func EnqueueResume(task ResumeTask) error {
+	if pipeline == nil {
+		return errors.New("resume pipeline not started")
+	}
+
+	openResumeEvents(task.ResumeId)
+	select {
+	case pipeline.tasks <- task:
+		publishResumeEvent(task.ResumeId, EventStatus, eventData{"status": models.ResumeStatusPending})
+		return nil
+	default:
+		publishResumeEvent(task.ResumeId, EventFailed, eventData{"status": models.ResumeStatusFailed, "error": ErrPipelineBusy.Error()})
+		return ErrPipelineBusy
+	}
+}
+
+// InitialResumeStages returns the Stages JSON of a freshly uploaded resume
+func InitialResumeStages() string {
+	stages := make([]models.ResumeStage, 0, 4)
+	for _, stage := range []string{models.StageExtracting, models.StageAnalyzing, models.StageUploading, models.StageFetchingJobs} {
+		stages = append(stages, models.ResumeStage{Stage: stage, Status: models.StageStatusPending})
+	}
+	data, _ := json.Marshal(stages)
+	return string(data)
+}
+
+func (p *resumePipeline) worker() {
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case task := <-p.tasks:
+			processResume(p.ctx, task)
+		}
+	}
+}
+
+// recoverInterruptedResumes fails pending and processing resumes last updated before cutoff,
+// their temp files and queued tasks are gone. Resumes saved before updated_at existed have none.
+func recoverInterruptedResumes(cutoff time.Time) {
+	result := config.DB.Model(&models.Resume{}).
+		Where("status IN ?", []string{models.ResumeStatusPending, models.ResumeStatusProcessing}).
+		Where("updated_at < ? OR updated_at IS NULL", cutoff).
+		Updates(map[string]interface{}{
+			"status": models.ResumeStatusFailed,
+			"error":  "processing was interrupted by a server restart, please upload the resume again",
+		})
+	if result.Error != nil {
+		fmt.Println("⚠️  Failed to recover interrupted resumes:", result.Error)
+	} else if result.RowsAffected > 0 {
+		fmt.Printf("⚠️  Marked %d interrupted resumes as failed\n", result.RowsAffected)
+	}
+}
+
+// processResume runs extraction, analysis, storage and job recommendations for one resume
+func processResume(ctx context.Context, task ResumeTask) {
+	defer os.Remove(task.FilePath) // Clean up temp file after processing
+
+	var resume models.Resume
+	if err := config.DB.First(&resume, task.ResumeId).Error; err != nil {
+		fmt.Printf("⚠️  Resume %d not found, skipping: %v\n", task.ResumeId, err)
+		return
+	}
+
+	run := newResumeRun(&resume)
+
+	// A panic fails this resume instead of taking down the server and the queued resumes.
+	// The PDF parser panics on some malformed files.
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("🔥 Panic while processing resume %d: %v\n%s", resume.Id, r, debug.Stack())
+			run.crash(fmt.Errorf("processing failed unexpectedly: %v", r))
+		}
+	}()
+
+	if err := run.begin(); err != nil {
+		return
+	}
+
+	// Extract text (PDF, DOCX, TXT or Markdown) BEFORE uploading and split it into sections
+	var resumeText string
+	var sections ParsedResume
+	err := run.stage(models.StageExtracting, func() error {
+		text, reused := reuseResumeText(&resume, task.TextFrom)
+		if !reused {
+			var err error
+			if text, err = extractResume(run, task.FilePath); err != nil {
+				return err
+			}
+		}
+		resumeText = text
+
+		sections = ParseResumeSections(resumeText)
+		sectionsJSON, _ := json.Marshal(sections)
+		resume.Sections = string(sectionsJSON)
+		fmt.Printf("📑 Parsed sections: %d positions, %d degrees, %d skills\n",
+			len(sections.Experience), len(sections.Education), len(sections.Skills))
+		return run.save("extraction_report", "sections")
+	})
+	if err != nil {
+		run.fail(err)
+		return
+	}
+
+	// Analyze the extracted text with optional job description
+	var skills []string
+	err = run.stage(models.StageAnalyzing, func() error {
+		analysis, err := AnalyzeResumeText(ctx, resumeText, task.JobDescription)
+		if err != nil {
+			fmt.Println("AI Analysis Error:", err)
+			return fmt.Errorf("failed to analyze resume with AI: %v", err)
+		}
+		fmt.Println("Analysis completed")
+		skills = applyAnalysis(&resume, analysis)
+		if err := run.save("analysis_result", "ats_score", "jd_match_score", "matching_skills", "missing_skills"); err != nil {
+			return err
+		}
+		if err := saveResumeAnalysis(resume.Id, task.JobDescription, task.JobDescriptionId, analysis); err != nil {
+			fmt.Println("⚠️  Failed to record analysis run:", err)
+		}
+		publishResumeEvent(resume.Id, EventAnalysis, analysisEventData(&resume))
+		return nil
+	})
+	if err != nil {
+		run.fail(err)
+		return
+	}
+
+	// upload to the configured file storage, unless an identical upload's file is reused
+	if resume.FileId != "" {
+		resume.FileUrl = ResumeFilePath(resume.Id)
+		if err := run.save("file_url"); err != nil {
+			run.fail(err)
+			return
+		}
+		run.skip(models.StageUploading, "identical file already stored")
+	} else {
+		err = run.stage(models.StageUploading, func() error {
+			fileId, err := UploadResume(ctx, task.FilePath)
+			if err != nil {
+				fmt.Println("Upload Error:", err)
+				return fmt.Errorf("failed to upload resume: %v", err)
+			}
+			fmt.Println("Uploaded file:", fileId)
+			resume.FileUrl = ResumeFilePath(resume.Id)
+			resume.FileId = fileId
+			if err := run.save("file_url", "file_id"); err != nil {
+				if err == errResumeDeleted {
+					// deleted while we were uploading, don't leave the file behind
+					if delErr := DeleteResumeFile(context.Background(), fileId); delErr != nil {
+						fmt.Println("⚠️  Failed to delete file of deleted resume:", delErr)
+					}
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			run.fail(err)
+			return
+		}
+	}
+
+	// Fetch job recommendations based on extracted skills (non-fatal).
+	// The parsed skills section and location fill in what the analyzer and the form left out.
+	if len(skills) == 0 {
+		skills = sections.Skills
+	}
+	jobPrefs := task.JobPrefs
+	if len(jobPrefs.Locations) == 0 && sections.Contact.Location != "" {
+		jobPrefs.Locations = []string{sections.Contact.Location}
+	}
+	if len(skills) == 0 {
+		fmt.Println("⚠️  No skills extracted, skipping job recommendations")
+		run.skip(models.StageFetchingJobs, "no skills extracted")
+	} else {
+		err = run.stage(models.StageFetchingJobs, func() error {
+			return saveJobRecommendations(ctx, resume.Id, skills, jobPrefs)
+		})
+		if errors.Is(err, errResumeDeleted) {
+			run.fail(err)
+			return
+		}
+	}
+
+	run.finish()
+}
+
+// extractResume extracts the text of the uploaded file and stores it. The extraction
+// report is kept on the resume, and saved right away when no text could be extracted.
+func extractResume(run *resumeRun, filePath string) (string, error) {
+	// the temp file has a server generated name, the stored format keeps Markdown apart from text
+	fileName, _ := ResumeFileName(*run.resume)
+	text, report, err := ExtractResumeText(filePath, fileName)
+	if report != nil {
+		reportJSON, _ := json.Marshal(report)
+		run.resume.ExtractionReport = string(reportJSON)
+		fmt.Printf("📋 Extraction report: %d/%d pages with text, %d warnings\n",
+			report.PagesExtracted, report.PagesTotal, len(report.Warnings))
+	}
+	if err != nil {
+		fmt.Println("Text Extraction Error:", err)
+		if report == nil {
+			return "", fmt.Errorf("failed to extract text from resume: %v", err)
+		}
+		// the report tells the user why nothing could be read
+		if saveErr := run.save("extraction_report"); saveErr != nil {
+			return "", saveErr
+		}
+		if len(report.Warnings) > 0 {
+			return "", fmt.Errorf("failed to extract text from resume: %v. %s", err, report.Warnings[0].Message)
+		}
+		return "", fmt.Errorf("failed to extract text from resume: %v", err)
+	}
+	fmt.Printf("Extracted text length: %d (%s)\n", len(text), report.Format)
+	if err := SaveResumeText(run.resume.Id, report.Format, text); err != nil {
+		fmt.Println("⚠️  Failed to store extracted text:", err)
+	}
+	return text, nil
+}
+
+// reuseResumeText copies the stored text and extraction report of an identical upload,
+// ok is false when it has no text from the current extractor
+func reuseResumeText(resume *models.Resume, sourceId uint) (string, bool) {
+	if sourceId == 0 {
+		return "", false
+	}
+	stored, err := StoredResumeText(sourceId)
+	if err != nil || !ResumeTextIsCurrent(stored) {
+		return "", false
+	}
+	var source models.Resume
+	if err := config.DB.Select("id", "extraction_report").First(&source, sourceId).Error; err != nil {
+		return "", false
+	}
+	if err := SaveResumeText(resume.Id, stored.Format, stored.Text); err != nil {
+		fmt.Println("⚠️  Failed to store extracted text:", err)
+	}
+	resume.ExtractionReport = source.ExtractionReport
+	fmt.Printf("♻️  Reusing extracted text of identical resume %d (%d chars)\n", sourceId, len(stored.Text))
+	return stored.Text, true
+}
+
+// saveJobRecommendations fetches jobs for the skills and stores them for the resume
+func saveJobRecommendations(ctx context.Context, resumeId uint, skills []string, prefs JobPreferences) error {
+	fmt.Printf("🔍 Fetching job recommendations for %d skills\n", len(skills))
+	jobs, err := FetchJobRecommendations(ctx, skills, recommendedJobsLimit, prefs)
+	if err != nil {
+		fmt.Println("⚠️  Job fetch error (non-fatal):", err)
+		return fmt.Errorf("failed to fetch job recommendations: %v", err)
+	}
+	fmt.Printf("✅ Fetched %d job recommendations\n", len(jobs))
+
+	// Save job recommendations to database
+	saved := 0
+	for _, job := range jobs {
+		// Synthetic fallback jobs are only stored in demo mode
+		if !ShouldPersistJob(job) {
+			publishResumeEvent(resumeId, EventJob, job)
+			continue
+		}
+
+		matchedSkills, _ := json.Marshal(job.MatchedSkills)
+		jobRec := models.JobRecommendation{
+			ResumeId:      resumeId,
+			Title:         job.Title,
+			Company:       job.Company,
+			Location:      job.Location,
+			Description:   job.Description,
+			Salary:        job.Salary,
+			JobUrl:        job.JobUrl,
+			PostedDate:    job.PostedDate,
+			JobType:       job.JobType,
+			Source:        job.Source,
+			SourceId:      job.SourceId,
+			Score:         job.Score,
+			MatchedSkills: string(matchedSkills),
+			Synthetic:     job.Synthetic,
+		}
+		if err := config.DB.Create(&jobRec).Error; err != nil {
+			fmt.Printf("⚠️  Failed to save job recommendation: %v\n", err)
+			// Continue saving other jobs even if one fails
+			continue
+		}
+		saved++
+		publishResumeEvent(resumeId, EventJob, jobRec)
+	}
+	fmt.Printf("✅ Saved %d job recommendations to database\n", saved)
+	return nil
+}
+
+// applyAnalysis stores the analysis on the resume and returns the extracted skills
+func applyAnalysis(resume *models.Resume, analysis *AnalysisResult) []string {
+	// Save full analysis JSON and its scores and skills in their own columns
+	data, _ := json.Marshal(analysis)
+	resume.AnalysisResult = string(data)
+	resume.AtsScore = analysis.AtsScore
+	resume.JdMatchScore = analysis.JdMatchScore
+	matchingSkills, _ := json.Marshal(analysis.MatchingSkills)
+	resume.MatchingSkills = string(matchingSkills)
+	missingSkills, _ := json.Marshal(analysis.MissingSkills)
+	resume.MissingSkills = string(missingSkills)
+
+	fmt.Printf("✅ Analysis (%s, schema v%d): ATS score %d, %d skills\n",
+		analysis.Analyzer, analysis.SchemaVersion, analysis.AtsScore, len(analysis.Skills))
+	return analysis.Skills
+}
+
+// AnalysisSkills returns the skills of a stored analysis (Resume.AnalysisResult)
+func AnalysisSkills(analysis string) []string {
+	var stored AnalysisResult
+	if err := json.Unmarshal([]byte(analysis), &stored); err != nil {
+		return nil
+	}
+	return stored.Skills
+}
+
+// resumeRun tracks the status and per-stage progress of one resume while it is processed
+type resumeRun struct {
+	resume *models.Resume
+	stages []models.ResumeStage
+}
+
+func newResumeRun(resume *models.Resume) *resumeRun {
+	var stages []models.ResumeStage
+	if err := json.Unmarshal([]byte(resume.Stages), &stages); err != nil || len(stages) == 0 {
+		json.Unmarshal([]byte(InitialResumeStages()), &stages)
+	}
+	return &resumeRun{resume: resume, stages: stages}
+}
+
+func (r *resumeRun) begin() error {
+	r.resume.Status = models.ResumeStatusProcessing
+	return r.save("status")
+}
+
+// stage runs fn as the given stage, recording its start, end and error
+func (r *resumeRun) stage(name string, fn func() error) error {
+	now := time.Now()
+	r.resume.Stage = name
+	r.update(name, func(s *models.ResumeStage) {
+		s.Status = models.StageStatusRunning
+		s.StartedAt = &now
+	})
+	if err := r.save("stage", "stages"); err != nil {
+		return err
+	}
+	r.publishStage(name)
+
+	err := fn()
+	if errors.Is(err, errResumeDeleted) {
+		return err
+	}
+
+	finished := time.Now()
+	r.update(name, func(s *models.ResumeStage) {
+		s.FinishedAt = &finished
+		if err != nil {
+			s.Status = models.StageStatusFailed
+			s.Error = err.Error()
+		} else {
+			s.Status = models.StageStatusDone
+		}
+	})
+	if saveErr := r.save("stages"); saveErr != nil {
+		return saveErr
+	}
+	r.publishStage(name)
+	return err
+}
+
+// skip marks a stage as skipped
+func (r *resumeRun) skip(name, reason string) {
+	r.update(name, func(s *models.ResumeStage) {
+		s.Status = models.StageStatusSkipped
+		s.Error = reason
+	})
+	r.save("stages")
+	r.publishStage(name)
+}
+
+// fail marks the resume as failed
+func (r *resumeRun) fail(err error) {
+	if errors.Is(err, errResumeDeleted) {
+		fmt.Printf("⚠️  Resume %d was deleted during processing\n", r.resume.Id)
+		publishResumeEvent(r.resume.Id, EventFailed, eventData{"status": models.ResumeStatusFailed, "error": err.Error()})
+		return
+	}
+	r.resume.Status = models.ResumeStatusFailed
+	r.resume.Error = err.Error()
+	r.save("status", "error")
+	publishResumeEvent(r.resume.Id, EventFailed, eventData{"status": r.resume.Status, "error": r.resume.Error})
+}
+
+// crash marks the running stage and the resume as failed after a panic
+func (r *resumeRun) crash(err error) {
+	if r.resume.Stage != "" {
+		finished := time.Now()
+		r.update(r.resume.Stage, func(s *models.ResumeStage) {
+			if s.Status == models.StageStatusRunning {
+				s.Status = models.StageStatusFailed
+				s.Error = err.Error()
+				s.FinishedAt = &finished
+			}
+		})
+		r.save("stages")
+	}
+	r.fail(err)
+}
+
+// finish marks the resume as done
+func (r *resumeRun) finish() {
+	r.resume.Status = models.ResumeStatusDone
+	r.resume.Stage = models.StageDone
+	r.save("status", "stage")
+	publishResumeEvent(r.resume.Id, EventDone, eventData{"status": r.resume.Status})
+	fmt.Printf("✅ Resume %d processed\n", r.resume.Id)
+}
+
+// publishStage sends the current progress of a stage to event subscribers
+func (r *resumeRun) publishStage(name string) {
+	for _, s := range r.stages {
+		if s.Stage == name {
+			publishResumeEvent(r.resume.Id, EventStage, s)
+			return
+		}
+	}
+}
+
+func (r *resumeRun) update(name string, fn func(*models.ResumeStage)) {
+	for i := range r.stages {
+		if r.stages[i].Stage == name {
+			fn(&r.stages[i])
+			return
+		}
+	}
+	r.stages = append(r.stages, models.ResumeStage{Stage: name})
+	fn(&r.stages[len(r.stages)-1])
+}
+
+// save writes the given columns of the resume. It never re-creates a deleted
+// resume and returns errResumeDeleted if the row is gone.
+func (r *resumeRun) save(columns ...string) error {
+	if data, err := json.Marshal(r.stages); err == nil {
+		r.resume.Stages = string(data)
+	}
+
+	result := config.DB.Model(r.resume).Select(columns).Updates(r.resume)
+	if result.Error != nil {
+		fmt.Println("Db save error:", result.Error)
+		return fmt.Errorf("failed to update resume: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errResumeDeleted
+	}
+	return nil
+}
+
+// envInt reads a positive integer from the environment
+func envInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		fmt.Printf("⚠️  Invalid %s %q, using %d\n", key, v, fallback)
+	}
+	return fallback
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dryRunDB points config.DB at a database that never connects. Loading a resume
+// returns a copy of stored, updates report one affected row and are recorded in
+// the returned slice, in order.
+func dryRunDB(t *testing.T, stored models.Resume) *[]models.Resume {
+	t.Helper()
+	db := fakeDB(t)
+
+	var updates []models.Resume
+	db.Callback().Query().After("gorm:query").Register("test:load_resume", func(tx *gorm.DB) {
+		if resume, ok := tx.Statement.Dest.(*models.Resume); ok {
+			*resume = stored
+			tx.RowsAffected = 1
+		}
+	})
+	db.Callback().Update().After("gorm:update").Register("test:record_update", func(tx *gorm.DB) {
+		if resume, ok := tx.Statement.Model.(*models.Resume); ok {
+			updates = append(updates, *resume)
+		}
+		tx.RowsAffected = 1
+	})
+
+	useDB(t, db)
+	return &updates
+}
+
+// A PDF the parser panics on fails its resume instead of crashing the worker
+func TestProcessResumeCorruptPdf(t *testing.T) {
+	t.Setenv("PDF_EXTRACT_MODE", PdfExtractLayout)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "corrupt.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "upload.pdf")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := dryRunDB(t, models.Resume{
+		Id:     7,
+		Status: models.ResumeStatusPending,
+		Stages: InitialResumeStages(),
+	})
+
+	processResume(context.Background(), ResumeTask{ResumeId: 7, FilePath: path})
+
+	if len(*updates) == 0 {
+		t.Fatal("resume was never updated")
+	}
+	last := (*updates)[len(*updates)-1]
+	if last.Status != models.ResumeStatusFailed {
+		t.Errorf("status = %q, want %q", last.Status, models.ResumeStatusFailed)
+	}
+	if !strings.Contains(last.Error, "processing failed unexpectedly") {
+		t.Errorf("error = %q, want the recovered panic", last.Error)
+	}
+	if !strings.Contains(last.Stages, `"stage":"extracting","status":"failed"`) {
+		t.Errorf("extracting stage not marked failed: %s", last.Stages)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("temp file %s was not removed", path)
+	}
+}
+
+// Only resumes nobody updated since the cutoff are failed, other instances may still be processing the rest
+func TestRecoverInterruptedResumesCutoff(t *testing.T) {
+	dryRunDB(t, models.Resume{})
+
+	var sql string
+	var vars []interface{}
+	config.DB.Callback().Update().After("gorm:update").Register("test:record_sql", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+		vars = tx.Statement.Vars
+	})
+
+	cutoff := time.Now().Add(-15 * time.Minute)
+	recoverInterruptedResumes(cutoff)
+
+	if !strings.Contains(sql, "status IN") || !strings.Contains(sql, "updated_at < $") {
+		t.Fatalf("update doesn't filter on status and updated_at: %s", sql)
+	}
+	found := false
+	for _, v := range vars {
+		if ts, ok := v.(time.Time); ok && ts.Equal(cutoff) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("cutoff %s not among the query values %v", cutoff, vars)
+	}
+}
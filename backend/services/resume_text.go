@@ -0,0 +1,162 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveResumeText stores the extracted text of a resume, replacing an earlier extraction
+func SaveResumeText(resumeId uint, format, text string) error {
+	record := models.ResumeText{
+		ResumeId: resumeId,
+		// PostgreSQL text columns can't hold NUL bytes
+		Text:             strings.ReplaceAll(text, "\x00", ""),
+		Format:           format,
+		ExtractorVersion: ExtractorVersion(format),
+	}
+	return config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resume_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "format", "extractor_version", "updated_at"}),
+	}).Create(&record).Error
+}
+
+// StoredResumeText returns the stored text of a resume, nil when there is none
+func StoredResumeText(resumeId uint) (*models.ResumeText, error) {
+	var record models.ResumeText
+	err := config.DB.Where("resume_id = ?", resumeId).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ResumeTextIsCurrent reports whether stored text came from the current extractor
+func ResumeTextIsCurrent(record *models.ResumeText) bool {
+	return record != nil && record.ExtractorVersion == ExtractorVersion(record.Format)
+}
+
+// ResumeText returns the text of a processed resume: the stored text when the current
+// extractor produced it, otherwise the stored file is extracted again and its text stored
+func ResumeText(ctx context.Context, resume *models.Resume) (string, error) {
+	stored, err := StoredResumeText(resume.Id)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load stored text of resume %d: %v\n", resume.Id, err)
+	}
+	if ResumeTextIsCurrent(stored) {
+		return stored.Text, nil
+	}
+	return reextractResumeText(ctx, resume)
+}
+
+// reextractResumeText downloads the stored file of a resume, extracts its text and stores it
+func reextractResumeText(ctx context.Context, resume *models.Resume) (string, error) {
+	if resume.FileId == "" {
+		return "", fmt.Errorf("resume %d has no stored file", resume.Id)
+	}
+
+	file, err := OpenResumeFile(ctx, resume.FileId)
+	if err != nil {
+		return "", fmt.Errorf("failed to load resume file: %w", err)
+	}
+	defer file.Close()
+
+	tmp, err := os.CreateTemp("", "resume-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, file); err != nil {
+		return "", fmt.Errorf("failed to load resume file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	fileName, _ := ResumeFileName(*resume)
+	text, report, err := ExtractResumeText(tmp.Name(), fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract text from resume: %w", err)
+	}
+	if err := SaveResumeText(resume.Id, report.Format, text); err != nil {
+		fmt.Printf("⚠️  Failed to store text of resume %d: %v\n", resume.Id, err)
+	}
+	return text, nil
+}
+
+// ResumeTextBackfill counts the outcome of BackfillResumeTexts
+type ResumeTextBackfill struct {
+	Checked   int
+	Extracted int
+	UpToDate  int
+	Failed    int
+	FailedIds []uint
+	LastId    uint // last resume checked, pass it as afterId to continue
+}
+
+// BackfillResumeTexts extracts and stores the text of processed resumes that have no
+// stored text or text from an older extractor version, all of them with force.
+// It walks resumes by id after afterId, at most limit of them (0 for all).
+func BackfillResumeTexts(ctx context.Context, afterId uint, limit int, force bool) (ResumeTextBackfill, error) {
+	const batchSize = 100
+	var result ResumeTextBackfill
+	result.LastId = afterId
+
+	for limit <= 0 || result.Checked < limit {
+		size := batchSize
+		if limit > 0 && limit-result.Checked < size {
+			size = limit - result.Checked
+		}
+
+		var resumes []models.Resume
+		err := config.DB.
+			Where("id > ? AND status = ? AND file_id <> ''", result.LastId, models.ResumeStatusDone).
+			Order("id").Limit(size).Find(&resumes).Error
+		if err != nil {
+			return result, err
+		}
+		if len(resumes) == 0 {
+			break
+		}
+
+		for i := range resumes {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			resume := &resumes[i]
+			result.Checked++
+			result.LastId = resume.Id
+
+			if !force {
+				stored, err := StoredResumeText(resume.Id)
+				if err == nil && ResumeTextIsCurrent(stored) {
+					result.UpToDate++
+					continue
+				}
+			}
+
+			if _, err := reextractResumeText(ctx, resume); err != nil {
+				fmt.Printf("⚠️  Resume %d: %v\n", resume.Id, err)
+				result.Failed++
+				result.FailedIds = append(result.FailedIds, resume.Id)
+				continue
+			}
+			result.Extracted++
+			fmt.Printf("📝 Resume %d: text extracted\n", resume.Id)
+		}
+	}
+	return result, nil
+}
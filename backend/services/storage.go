@@ -1,45 +1,214 @@
 package services
 
 import (
+	"backend/config"
+	"backend/models"
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/appwrite/sdk-for-go/appwrite"
 	"github.com/appwrite/sdk-for-go/file"
+	"github.com/appwrite/sdk-for-go/query"
+	"github.com/appwrite/sdk-for-go/storage"
 )
 
-func UploadResume(filePath string) (string, error) {
+// FileStorage stores uploaded resume files. Files are private: they are only
+// read through the server (GET /api/resume/:id/file), never linked directly.
+type FileStorage interface {
+	// Put stores the local file and returns its ID in the storage backend
+	Put(ctx context.Context, filePath string) (string, error)
+	// Get opens a stored file for reading
+	Get(ctx context.Context, fileId string) (io.ReadCloser, error)
+	// Delete removes a stored file
+	Delete(ctx context.Context, fileId string) error
+	// List returns the stored files this service owns (IDs starting with fileIdPrefix),
+	// used to find orphaned files
+	List(ctx context.Context) ([]StoredFile, error)
+}
+
+// fileIdPrefix starts the ID of every file this service stores. The reconciler
+// never lists or deletes files without it, whatever else shares the bucket.
+const fileIdPrefix = "resume-"
+
+// StoredFile is a file in the storage backend
+type StoredFile struct {
+	Id        string
+	CreatedAt time.Time // zero when the backend didn't report it
+}
+
+var fileStorage FileStorage
+
+// InitFileStorage selects the storage backend from STORAGE_BACKEND:
+// "appwrite" (default), "local" or "s3". It fails when there is no key to sign file links with.
+func InitFileStorage() error {
+	if linkSecret() == "" {
+		return ErrNoLinkSecret
+	}
+
+	backend := strings.ToLower(os.Getenv("STORAGE_BACKEND"))
+
+	var err error
+	switch backend {
+	case "", "appwrite":
+		backend = "appwrite"
+		fileStorage, err = newAppwriteStorage()
+	case "local":
+		fileStorage, err = newLocalStorage()
+	case "s3":
+		fileStorage, err = newS3Storage()
+	default:
+		err = fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🗂️  File storage:", backend)
+	return nil
+}
+
+// Storage returns the configured file storage backend
+func Storage() FileStorage {
+	return fileStorage
+}
+
+// UploadResume stores a resume file and returns its ID
+func UploadResume(ctx context.Context, filePath string) (string, error) {
+	if fileStorage == nil {
+		return "", fmt.Errorf("file storage not initialized")
+	}
+	return fileStorage.Put(ctx, filePath)
+}
+
+// OpenResumeFile opens a stored resume file for reading
+func OpenResumeFile(ctx context.Context, fileId string) (io.ReadCloser, error) {
+	if fileStorage == nil {
+		return nil, fmt.Errorf("file storage not initialized")
+	}
+	return fileStorage.Get(ctx, fileId)
+}
+
+// DeleteResumeFile removes a stored resume file once no resume references it anymore
+// (identical uploads share one file). Call it after deleting the resume row.
+func DeleteResumeFile(ctx context.Context, fileId string) error {
+	if fileId == "" {
+		return nil
+	}
+	if fileStorage == nil {
+		return fmt.Errorf("file storage not initialized")
+	}
+
+	var refs int64
+	if err := config.DB.Model(&models.Resume{}).Where("file_id = ?", fileId).Count(&refs).Error; err != nil {
+		return err
+	}
+	if refs > 0 {
+		return nil
+	}
+	return fileStorage.Delete(ctx, fileId)
+}
+
+// appwriteStorage stores files in an Appwrite bucket
+type appwriteStorage struct {
+	storage   *storage.Storage
+	endpoint  string
+	projectId string
+	bucketId  string
+}
+
+func newAppwriteStorage() (*appwriteStorage, error) {
+	s := &appwriteStorage{
+		endpoint:  os.Getenv("APPWRITE_ENDPOINT"),
+		projectId: os.Getenv("APPWRITE_PROJECT_ID"),
+		bucketId:  os.Getenv("APPWRITE_BUCKET_ID"),
+	}
+	if s.endpoint == "" || s.projectId == "" || s.bucketId == "" {
+		return nil, fmt.Errorf("APPWRITE_ENDPOINT, APPWRITE_PROJECT_ID and APPWRITE_BUCKET_ID are required")
+	}
+
 	client := appwrite.NewClient(
-		appwrite.WithEndpoint(os.Getenv("APPWRITE_ENDPOINT")),
-		appwrite.WithProject(os.Getenv("APPWRITE_PROJECT_ID")),
+		appwrite.WithEndpoint(s.endpoint),
+		appwrite.WithProject(s.projectId),
 		appwrite.WithKey(os.Getenv("APPWRITE_API_KEY")),
 	)
+	s.storage = appwrite.NewStorage(client)
+	return s, nil
+}
 
-	storage := appwrite.NewStorage(client)
-
+func (s *appwriteStorage) Put(ctx context.Context, filePath string) (string, error) {
 	// Create InputFile from the file path
 	inputFile := file.NewInputFile(filePath, filepath.Base(filePath))
 
-	// Upload to the bucket
-	// Note: Configure bucket permissions in Appwrite Console for public access
+	fileId, err := newFileId("")
+	if err != nil {
+		return "", err
+	}
+
+	// Upload to the bucket without permissions, so only the API key can read it back.
+	// The bucket itself must not grant read access to "any" in the Appwrite Console.
 	// CreateFile signature: CreateFile(bucketId string, fileId string, file file.InputFile, permissions ...string)
-	uploaded, err := storage.CreateFile(
-		os.Getenv("APPWRITE_BUCKET_ID"),
-		"unique()",
-		inputFile,
-	)
+	uploaded, err := s.storage.CreateFile(s.bucketId, fileId, inputFile)
 	if err != nil {
 		return "", fmt.Errorf("Failed to upload to appwrite %v", err)
 	}
+	return uploaded.Id, nil
+}
 
-	// Build public URL for viewing and download
-	fileURL := fmt.Sprintf("%s/storage/buckets/%s/files/%s/view?project=%s",
-		os.Getenv("APPWRITE_ENDPOINT"),
-		os.Getenv("APPWRITE_BUCKET_ID"),
-		uploaded.Id,
-		os.Getenv("APPWRITE_PROJECT_ID"),
-	)
+func (s *appwriteStorage) Get(ctx context.Context, fileId string) (io.ReadCloser, error) {
+	data, err := s.storage.GetFileDownload(s.bucketId, fileId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download from appwrite: %v", err)
+	}
+	return io.NopCloser(bytes.NewReader(*data)), nil
+}
 
-	return fileURL, nil
+func (s *appwriteStorage) Delete(ctx context.Context, fileId string) error {
+	if _, err := s.storage.DeleteFile(s.bucketId, fileId); err != nil {
+		return fmt.Errorf("failed to delete from appwrite: %v", err)
+	}
+	return nil
+}
+
+// appwriteListPage is how many files are fetched per ListFiles call
+const appwriteListPage = 100
+
+func (s *appwriteStorage) List(ctx context.Context) ([]StoredFile, error) {
+	var files []StoredFile
+	cursor := ""
+	for {
+		queries := []string{query.Limit(appwriteListPage)}
+		if cursor != "" {
+			queries = append(queries, query.CursorAfter(cursor))
+		}
+
+		page, err := s.storage.ListFiles(s.bucketId, s.storage.WithListFilesQueries(queries))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list appwrite files: %v", err)
+		}
+		for _, f := range page.Files {
+			if !strings.HasPrefix(f.Id, fileIdPrefix) {
+				continue
+			}
+			createdAt, err := time.Parse(time.RFC3339Nano, f.CreatedAt)
+			if err != nil {
+				// left zero, the reconciler keeps files of unknown age
+				fmt.Printf("⚠️  Appwrite file %s has an unreadable creation time %q: %v\n", f.Id, f.CreatedAt, err)
+			}
+			files = append(files, StoredFile{Id: f.Id, CreatedAt: createdAt})
+		}
+
+		if len(page.Files) < appwriteListPage {
+			return files, nil
+		}
+		cursor = page.Files[len(page.Files)-1].Id
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
 }
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReconcileInterval = 6 * time.Hour
+	// defaultOrphanMinAge keeps files that may still be in the middle of being
+	// uploaded and saved on their resume
+	defaultOrphanMinAge = time.Hour
+	// defaultOrphanMaxPercent is the share of files that may be orphaned before the
+	// reconciler suspects the wrong database and deletes nothing
+	defaultOrphanMaxPercent = 20
+)
+
+// ReconcileReport is the outcome of one storage reconciliation run
+type ReconcileReport struct {
+	Files      int      `json:"files"`       // files this service owns in the storage backend
+	Referenced int      `json:"referenced"`  // files referenced by a resume
+	TooRecent  int      `json:"too_recent"`  // unreferenced files younger than the minimum age, kept
+	UnknownAge int      `json:"unknown_age"` // unreferenced files without creation time, kept
+	Deleted    []string `json:"deleted"`     // orphaned files removed (or that would be, in dry run)
+	Failed     int      `json:"failed"`      // orphaned files that could not be removed
+	DryRun     bool     `json:"dry_run"`
+}
+
+// StartStorageReconciler periodically removes stored files no resume references,
+// e.g. left behind when deleting the file failed. Disabled with STORAGE_RECONCILE_INTERVAL=0,
+// it only reports them until STORAGE_RECONCILE_DRY_RUN=false.
+func StartStorageReconciler(ctx context.Context) {
+	interval := envDuration(defaultReconcileInterval, "STORAGE_RECONCILE_INTERVAL")
+	if interval == 0 {
+		fmt.Println("🧹 Storage reconciler disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			report, err := ReconcileStorage(ctx)
+			if err != nil {
+				fmt.Println("⚠️  Storage reconciliation failed:", err)
+			} else {
+				fmt.Printf("🧹 Storage reconciled: %d files, %d orphaned removed, %d failed (dry run: %v)\n",
+					report.Files, len(report.Deleted), report.Failed, report.DryRun)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fmt.Printf("🧹 Storage reconciler started: every %s\n", interval)
+}
+
+// ReconcileStorage deletes stored files that no resume references and that are older
+// than STORAGE_ORPHAN_MIN_AGE. It only reports them unless STORAGE_RECONCILE_DRY_RUN=false,
+// and deletes nothing when no resume was loaded or more than STORAGE_ORPHAN_MAX_PERCENT
+// of the files look orphaned.
+func ReconcileStorage(ctx context.Context) (ReconcileReport, error) {
+	report := ReconcileReport{DryRun: !strings.EqualFold(os.Getenv("STORAGE_RECONCILE_DRY_RUN"), "false")}
+	if fileStorage == nil {
+		return report, fmt.Errorf("file storage not initialized")
+	}
+	minAge := envDuration(defaultOrphanMinAge, "STORAGE_ORPHAN_MIN_AGE")
+	maxPercent := envInt("STORAGE_ORPHAN_MAX_PERCENT", defaultOrphanMaxPercent)
+
+	// List the files before loading the resumes, so a file uploaded in between
+	// is either too recent or already referenced
+	files, err := fileStorage.List(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Files = len(files)
+
+	var resumes []models.Resume
+	if err := config.DB.Select("id", "file_id").Find(&resumes).Error; err != nil {
+		return report, fmt.Errorf("failed to load resumes: %v", err)
+	}
+
+	referenced := make(map[string]bool, len(resumes))
+	for _, resume := range resumes {
+		if resume.FileId != "" {
+			referenced[resume.FileId] = true
+		}
+	}
+
+	var orphans []string
+	for _, f := range files {
+		if !strings.HasPrefix(f.Id, fileIdPrefix) {
+			continue // not ours
+		}
+		if referenced[f.Id] {
+			report.Referenced++
+			continue
+		}
+		if f.CreatedAt.IsZero() {
+			report.UnknownAge++
+			continue
+		}
+		if time.Since(f.CreatedAt) < minAge {
+			report.TooRecent++
+			continue
+		}
+		orphans = append(orphans, f.Id)
+	}
+	if len(orphans) == 0 {
+		return report, nil
+	}
+
+	// An empty or wrong database makes every file look orphaned
+	if len(referenced) == 0 {
+		return report, fmt.Errorf("refusing to delete %d files: no resume references a file", len(orphans))
+	}
+	if len(orphans)*100 > report.Files*maxPercent {
+		return report, fmt.Errorf("refusing to delete %d of %d files: more than %d%% look orphaned",
+			len(orphans), report.Files, maxPercent)
+	}
+
+	for _, id := range orphans {
+		if !report.DryRun {
+			if err := fileStorage.Delete(ctx, id); err != nil {
+				fmt.Printf("⚠️  Failed to delete orphaned file %s: %v\n", id, err)
+				report.Failed++
+				continue
+			}
+		}
+		report.Deleted = append(report.Deleted, id)
+	}
+	return report, nil
+}
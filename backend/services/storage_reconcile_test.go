@@ -0,0 +1,135 @@
+package services
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// memoryStorage is a FileStorage holding only the file list, recording deletions
+type memoryStorage struct {
+	files   []StoredFile
+	deleted []string
+}
+
+func (s *memoryStorage) Put(ctx context.Context, filePath string) (string, error) {
+	return "", fmt.Errorf("not supported")
+}
+
+func (s *memoryStorage) Get(ctx context.Context, fileId string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, fileId string) error {
+	s.deleted = append(s.deleted, fileId)
+	return nil
+}
+
+func (s *memoryStorage) List(ctx context.Context) ([]StoredFile, error) {
+	return s.files, nil
+}
+
+// resumesDB makes loading resumes return one row per file ID
+func resumesDB(t *testing.T, fileIds ...string) {
+	db := fakeDB(t)
+	db.Callback().Query().After("gorm:query").Register("test:load_resumes", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*[]models.Resume); ok {
+			for i, id := range fileIds {
+				*dest = append(*dest, models.Resume{Id: uint(i + 1), FileId: id})
+			}
+			tx.RowsAffected = int64(len(fileIds))
+		}
+	})
+	useDB(t, db)
+}
+
+func TestReconcileStorage(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	owned := func(n int) []StoredFile {
+		files := make([]StoredFile, n)
+		for i := range files {
+			files[i] = StoredFile{Id: fmt.Sprintf("resume-%02d", i), CreatedAt: old}
+		}
+		return files
+	}
+
+	tests := []struct {
+		name        string
+		dryRun      string
+		files       []StoredFile
+		referenced  []string
+		wantDeleted []string
+		wantErr     bool
+	}{
+		{
+			name:        "one orphan",
+			dryRun:      "false",
+			files:       owned(5),
+			referenced:  []string{"resume-00", "resume-01", "resume-02", "resume-03"},
+			wantDeleted: []string{"resume-04"},
+		},
+		{
+			name:       "dry run by default",
+			files:      owned(5),
+			referenced: []string{"resume-00", "resume-01", "resume-02", "resume-03"},
+		},
+		{
+			name:    "empty database",
+			dryRun:  "false",
+			files:   owned(5),
+			wantErr: true,
+		},
+		{
+			name:       "too many orphans",
+			dryRun:     "false",
+			files:      owned(5),
+			referenced: []string{"resume-00", "resume-01", "resume-02"},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown creation time",
+			dryRun:     "false",
+			files:      append(owned(5), StoredFile{Id: "resume-zero"}),
+			referenced: []string{"resume-00", "resume-01", "resume-02", "resume-03", "resume-04"},
+		},
+		{
+			name:       "recent file",
+			dryRun:     "false",
+			files:      append(owned(5), StoredFile{Id: "resume-new", CreatedAt: time.Now()}),
+			referenced: []string{"resume-00", "resume-01", "resume-02", "resume-03", "resume-04"},
+		},
+		{
+			name:       "file without our prefix",
+			dryRun:     "false",
+			files:      append(owned(5), StoredFile{Id: "avatar.png", CreatedAt: old}),
+			referenced: []string{"resume-00", "resume-01", "resume-02", "resume-03", "resume-04"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STORAGE_RECONCILE_DRY_RUN", tt.dryRun)
+			resumesDB(t, tt.referenced...)
+			store := &memoryStorage{files: tt.files}
+			previous := fileStorage
+			fileStorage = store
+			t.Cleanup(func() { fileStorage = previous })
+
+			report, err := ReconcileStorage(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(store.deleted, tt.wantDeleted) {
+				t.Errorf("deleted %v, want %v", store.deleted, tt.wantDeleted)
+			}
+			if tt.dryRun == "" && len(report.Deleted) != 1 {
+				t.Errorf("dry run reported %v, want the one orphan", report.Deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"backend/config"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// fakeDB returns a database that never connects: statements are built but not run.
+// Tests register callbacks on it to fake what queries return.
+func fakeDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// useDB points config.DB at db until the test ends
+func useDB(t *testing.T, db *gorm.DB) {
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = previous })
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	pdf "github.com/ledongthuc/pdf"
+)
+
+const (
+	defaultResumeMaxBytes = 10 << 20 // 10 MiB
+	defaultResumeMaxPages = 20
+	// pdfHeaderWindow is how far into the file the %PDF- header may start,
+	// PDF readers accept a few junk bytes before it. Also the window sniffed for other formats.
+	pdfHeaderWindow = 1024
+)
+
+// Upload error codes returned to the frontend in the "code" field
+const (
+	UploadErrFileRequired      = "file_required"
+	UploadErrFileTooLarge      = "file_too_large"
+	UploadErrEmptyFile         = "empty_file"
+	UploadErrUnsupportedFormat = "unsupported_format"
+	UploadErrUnreadableFile    = "unreadable_file"
+	UploadErrTooManyPages      = "too_many_pages"
+)
+
+// UploadError is an upload rejected by validation, Status is the HTTP status to answer with
+type UploadError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"error"`
+}
+
+func (e *UploadError) Error() string {
+	return e.Message
+}
+
+func uploadError(status int, code, format string, args ...interface{}) *UploadError {
+	return &UploadError{Status: status, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ResumeMaxBytes is the largest accepted resume file (RESUME_MAX_BYTES, default 10 MiB)
+func ResumeMaxBytes() int64 {
+	return int64(envInt("RESUME_MAX_BYTES", defaultResumeMaxBytes))
+}
+
+// ResumeMaxPages is the most pages an accepted PDF or DOCX resume may have (RESUME_MAX_PAGES, default 20)
+func ResumeMaxPages() int {
+	return envInt("RESUME_MAX_PAGES", defaultResumeMaxPages)
+}
+
+// AsUploadError converts errors from reading the multipart form into upload errors
+func AsUploadError(err error) *UploadError {
+	var uploadErr *UploadError
+	if errors.As(err, &uploadErr) {
+		return uploadErr
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return uploadError(http.StatusRequestEntityTooLarge, UploadErrFileTooLarge,
+			"resume file is too large, the maximum is %s", formatBytes(ResumeMaxBytes()))
+	}
+	return uploadError(http.StatusBadRequest, UploadErrFileRequired, "resume file required")
+}
+
+// SaveResumeUpload validates an uploaded resume and saves it to a new temp file named
+// after its sniffed format (PDF, DOCX, TXT or Markdown). It enforces the size limit and,
+// where the format has pages, the page limit. The caller removes the returned file.
+// Validation failures are *UploadError.
+func SaveResumeUpload(header *multipart.FileHeader) (string, string, error) {
+	maxBytes := ResumeMaxBytes()
+	if header.Size > maxBytes {
+		return "", "", uploadError(http.StatusRequestEntityTooLarge, UploadErrFileTooLarge,
+			"resume file is too large (%s), the maximum is %s", formatBytes(header.Size), formatBytes(maxBytes))
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open upload: %v", err)
+	}
+	defer src.Close()
+
+	// The temp name never contains the client's file name
+	dst, err := os.CreateTemp("", "resume-*.upload")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	path := dst.Name()
+
+	n, err := io.Copy(dst, io.LimitReader(src, maxBytes+1))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", "", fmt.Errorf("failed to save upload: %v", err)
+	}
+
+	format, err := validateResumeFile(path, SanitizeFilename(header.Filename), n, maxBytes)
+	if err != nil {
+		os.Remove(path)
+		return "", "", err
+	}
+
+	// The extension carries the format to the storage backend and downloads
+	finalPath := strings.TrimSuffix(path, ".upload") + "." + format
+	if err := os.Rename(path, finalPath); err != nil {
+		os.Remove(path)
+		return "", "", fmt.Errorf("failed to save upload: %v", err)
+	}
+	return finalPath, format, nil
+}
+
+// validateResumeFile checks a saved upload's size, format and page count
+func validateResumeFile(path, fileName string, size, maxBytes int64) (string, error) {
+	if size == 0 {
+		return "", uploadError(http.StatusUnprocessableEntity, UploadErrEmptyFile, "resume file is empty")
+	}
+	if size > maxBytes {
+		return "", uploadError(http.StatusRequestEntityTooLarge, UploadErrFileTooLarge,
+			"resume file is too large, the maximum is %s", formatBytes(maxBytes))
+	}
+
+	format, err := DetectResumeFormat(path, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	var pages int
+	switch format {
+	case FormatPDF:
+		pages, err = countPDFPages(path)
+	case FormatDOCX:
+		pages, err = countDocxPages(path)
+	}
+	if err != nil {
+		return "", uploadError(http.StatusUnprocessableEntity, UploadErrUnreadableFile,
+			"resume %s could not be read, it may be damaged or encrypted", strings.ToUpper(format))
+	}
+	if maxPages := ResumeMaxPages(); pages > maxPages {
+		return "", uploadError(http.StatusUnprocessableEntity, UploadErrTooManyPages,
+			"resume has %d pages, the maximum is %d", pages, maxPages)
+	}
+	return format, nil
+}
+
+// countPDFPages returns the number of pages of a PDF. The parser panics on some
+// malformed files, that is reported as an error.
+func countPDFPages(path string) (pages int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	file, reader, err := pdf.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return reader.NumPage(), nil
+}
+
+// countDocxPages returns the page count Word saved in docProps/app.xml, 0 when it
+// isn't there (the real count depends on layout, which only Word knows)
+func countDocxPages(path string) (int, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != "docProps/app.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return 0, err
+		}
+		defer rc.Close()
+
+		var props struct {
+			Pages int `xml:"Pages"`
+		}
+		if err := xml.NewDecoder(io.LimitReader(rc, 1<<20)).Decode(&props); err != nil {
+			return 0, nil
+		}
+		return props.Pages, nil
+	}
+	return 0, nil
+}
+
+// SanitizeFilename reduces a client supplied file name to a safe display name:
+// no directories, no control or path characters, at most 100 characters
+func SanitizeFilename(name string) string {
+	// Clients may send Windows paths
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r), strings.ContainsRune(`/\:*?"<>|`, r):
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.TrimSpace(name), ".")
+
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	if name == "" {
+		return "resume"
+	}
+	return name
+}
+
+// formatBytes formats a byte count for error messages
+func formatBytes(n int64) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	}
+	return fmt.Sprintf("%d KB", (n+1023)/1024)
+}
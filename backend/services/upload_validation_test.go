@@ -0,0 +1,185 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Jane Doe CV.pdf", "Jane Doe CV.pdf"},
+		{"unix traversal", "../../etc/passwd", "passwd"},
+		{"windows traversal", `..\..\Windows\System32\cmd.exe`, "cmd.exe"},
+		{"windows path", `C:\Users\jane\resume.docx`, "resume.docx"},
+		{"only dots", "..", "resume"},
+		{"hidden file", ".resume.pdf", "resume.pdf"},
+		{"empty", "", "resume"},
+		{"blank", "   ", "resume"},
+		{"control characters", "re\x00su\x1bme\n.pdf", "resume.pdf"},
+		{"tab", "my\tresume.pdf", "myresume.pdf"},
+		{"unicode space", "my\u00a0resume.pdf", "my resume.pdf"},
+		{"reserved characters", `a:b*c?"d<e>f|g.pdf`, "abcdefg.pdf"},
+		{"non ascii", "Lebenslauf Müller 履歴書.pdf", "Lebenslauf Müller 履歴書.pdf"},
+		{"long ascii", strings.Repeat("a", 150) + ".pdf", strings.Repeat("a", 100)},
+		{"long multibyte", strings.Repeat("é", 150), strings.Repeat("é", 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeFilename(tt.in); got != tt.want {
+				t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// uploadHeader builds the multipart file header a client uploading content as fileName sends
+func uploadHeader(t *testing.T, fileName string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("resume", fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	w.Close()
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["resume"][0]
+}
+
+// docxFile builds a Word document, pages is what Word saved in docProps/app.xml (0 leaves it out)
+func docxFile(t *testing.T, pages int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Jane Doe</w:t></w:r></w:p></w:body></w:document>`,
+	}
+	if pages > 0 {
+		files["docProps/app.xml"] = `<Properties><Pages>` + strconv.Itoa(pages) + `</Pages></Properties>`
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSaveResumeUploadRejections(t *testing.T) {
+	t.Setenv("RESUME_MAX_BYTES", "4096")
+	t.Setenv("RESUME_MAX_PAGES", "20")
+
+	zipNotDocx := func() []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		f, _ := w.Create("notes.txt")
+		f.Write([]byte("not a resume"))
+		w.Close()
+		return buf.Bytes()
+	}()
+
+	tests := []struct {
+		name       string
+		fileName   string
+		content    []byte
+		wantStatus int
+		wantCode   string
+	}{
+		{"too large", "resume.txt", bytes.Repeat([]byte("a"), 4097), http.StatusRequestEntityTooLarge, UploadErrFileTooLarge},
+		{"empty", "resume.pdf", nil, http.StatusUnprocessableEntity, UploadErrEmptyFile},
+		{"image", "resume.pdf", append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0, 1, 2, 3), http.StatusUnsupportedMediaType, UploadErrUnsupportedFormat},
+		{"zip without document", "resume.docx", zipNotDocx, http.StatusUnsupportedMediaType, UploadErrUnsupportedFormat},
+		{"invalid utf-8 text", "resume.txt", []byte("Jane \xff\xfe Doe"), http.StatusUnsupportedMediaType, UploadErrUnsupportedFormat},
+		{"damaged pdf", "resume.pdf", readTestdata(t, "corrupt.pdf"), http.StatusUnprocessableEntity, UploadErrUnreadableFile},
+		{"too many pages", "resume.docx", docxFile(t, 25), http.StatusUnprocessableEntity, UploadErrTooManyPages},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			t.Setenv("TMPDIR", tmp)
+
+			path, _, err := SaveResumeUpload(uploadHeader(t, tt.fileName, tt.content))
+			if err == nil {
+				os.Remove(path)
+				t.Fatalf("upload accepted as %s", path)
+			}
+			var uploadErr *UploadError
+			if !errors.As(err, &uploadErr) {
+				t.Fatalf("err = %v, want an *UploadError", err)
+			}
+			if uploadErr.Status != tt.wantStatus || uploadErr.Code != tt.wantCode {
+				t.Errorf("got %d %s (%s), want %d %s", uploadErr.Status, uploadErr.Code, uploadErr.Message, tt.wantStatus, tt.wantCode)
+			}
+
+			// rejected uploads leave no temp file behind
+			if entries, _ := os.ReadDir(tmp); len(entries) > 0 {
+				t.Errorf("temp files left behind: %v", entries)
+			}
+		})
+	}
+}
+
+func TestSaveResumeUploadAccepted(t *testing.T) {
+	t.Setenv("RESUME_MAX_PAGES", "1")
+
+	tests := []struct {
+		name       string
+		fileName   string
+		content    []byte
+		wantFormat string
+	}{
+		{"pdf at the page limit", "resume.pdf", readTestdata(t, "single_column.pdf"), FormatPDF},
+		{"pdf named otherwise", "resume.txt", readTestdata(t, "single_column.pdf"), FormatPDF},
+		{"docx without page count", "resume.docx", docxFile(t, 0), FormatDOCX},
+		{"text", "resume.txt", []byte("Jane Doe\nGo developer"), FormatText},
+		{"markdown", "../resume.md", []byte("# Jane Doe\n\nGo developer"), FormatMarkdown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, format, err := SaveResumeUpload(uploadHeader(t, tt.fileName, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(path)
+
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			if !strings.HasSuffix(path, "."+tt.wantFormat) {
+				t.Errorf("path %s doesn't end in .%s", path, tt.wantFormat)
+			}
+		})
+	}
+}
@@ -6,19 +6,30 @@ import Header from '@/components/Header';
 import ScoreGauge from '@/components/ScoreGauge';
 import SkillBadge from '@/components/SkillBadge';
 import JobCard from '@/components/JobCard';
-import { resumeAPI, getToken, JobRecommendation, parseSkills } from '@/lib/api';
+import { resumeAPI, getToken, JobRecommendation, JobFallbackMode, AtsWarning, ResumeStage, parseSkills } from '@/lib/api';
+
+// Progress shown while a stage of the upload is running
+const stageLabels: Record<string, string> = {
+  extracting: 'Reading Resume...',
+  analyzing: 'Analyzing Resume...',
+  uploading: 'Saving Resume...',
+  fetching_jobs: 'Finding Jobs...',
+};
 
 export default function DashboardPage() {
   const [file, setFile] = useState<File | null>(null);
   const [title, setTitle] = useState('');
   const [jobDescription, setJobDescription] = useState('');
   const [loading, setLoading] = useState(false);
+  const [progress, setProgress] = useState('');
   const [error, setError] = useState('');
   const [atsScore, setAtsScore] = useState<number | null>(null);
   const [jdMatchScore, setJdMatchScore] = useState<number | null>(null);
   const [matchingSkills, setMatchingSkills] = useState<string[]>([]);
   const [missingSkills, setMissingSkills] = useState<string[]>([]);
   const [jobs, setJobs] = useState<JobRecommendation[]>([]);
+  const [fallbackMode, setFallbackMode] = useState<JobFallbackMode>('');
+  const [atsWarnings, setAtsWarnings] = useState<AtsWarning[]>([]);
   const [showJobDescription, setShowJobDescription] = useState(false);
   const router = useRouter();
 
@@ -32,11 +43,11 @@ export default function DashboardPage() {
   const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>) => {
     if (e.target.files && e.target.files[0]) {
       const selectedFile = e.target.files[0];
-      if (selectedFile.type === 'application/pdf') {
+      if (/\.(pdf|docx|txt|md|markdown)$/i.test(selectedFile.name)) {
         setFile(selectedFile);
         setError('');
       } else {
-        setError('Please select a PDF file');
+        setError('Please select a PDF, DOCX, TXT or Markdown file');
         setFile(null);
       }
     }
@@ -50,10 +61,22 @@ export default function DashboardPage() {
     }
 
     setLoading(true);
+    setProgress('');
     setError('');
 
     try {
-      const response = await resumeAPI.upload(file, title, jobDescription || undefined);
+      const response = await resumeAPI.upload(
+        file,
+        title,
+        jobDescription || undefined,
+        false,
+        undefined,
+        (stage: ResumeStage) => {
+          if (stage.status === 'running' && stageLabels[stage.stage]) {
+            setProgress(stageLabels[stage.stage]);
+          }
+        }
+      );
       
       // Set the analysis results
       setAtsScore(response.ats_score);
@@ -61,6 +84,8 @@ export default function DashboardPage() {
       setMatchingSkills(parseSkills(response.matching_skills));
       setMissingSkills(parseSkills(response.missing_skills));
       setJobs(response.recommended_jobs || []);
+      setFallbackMode(response.fallback_mode || '');
+      setAtsWarnings(response.ats_warnings || []);
       
       // Reset form
       setFile(null);
@@ -107,7 +132,7 @@ export default function DashboardPage() {
                   Upload Your Resume
                 </h2>
                 <p className="text-sm text-slate-600 dark:text-slate-400">
-                  PDF format only • Maximum 10MB
+                  PDF, DOCX, TXT or Markdown • Maximum 10MB
                 </p>
               </div>
             </div>
@@ -135,12 +160,12 @@ export default function DashboardPage() {
 
               <div>
                 <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">
-                  Upload PDF Resume
+                  Upload Resume
                 </label>
                 <div className="relative">
                   <input
                     type="file"
-                    accept=".pdf"
+                    accept=".pdf,.docx,.txt,.md,.markdown"
                     onChange={handleFileChange}
                     className="hidden"
                     id="resume-upload"
@@ -168,7 +193,7 @@ export default function DashboardPage() {
                           Click to upload or drag and drop
                         </p>
                         <p className="text-xs text-slate-500 dark:text-slate-500 mt-1">
-                          PDF up to 10MB
+                          PDF, DOCX, TXT or MD up to 10MB
                         </p>
                       </div>
                     )}
@@ -209,7 +234,7 @@ export default function DashboardPage() {
                       <circle className="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4"></circle>
                       <path className="opacity-75" fill="currentColor" d="M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z"></path>
                     </svg>
-                    Analyzing Resume...
+                    {progress || 'Analyzing Resume...'}
                   </span>
                 ) : (
                   <span className="flex items-center justify-center">
@@ -253,6 +278,21 @@ export default function DashboardPage() {
                   </div>
                 )}
               </div>
+
+              {atsWarnings.length > 0 && (
+                <div className="mt-8 p-4 bg-amber-50 dark:bg-amber-900/20 border border-amber-200 dark:border-amber-800 rounded-lg">
+                  <h3 className="text-sm font-semibold text-amber-800 dark:text-amber-300 mb-2">
+                    ATS readability warnings
+                  </h3>
+                  <ul className="list-disc list-inside space-y-1">
+                    {atsWarnings.map((warning) => (
+                      <li key={warning.code} className="text-sm text-amber-700 dark:text-amber-400">
+                        {warning.message}
+                      </li>
+                    ))}
+                  </ul>
+                </div>
+              )}
             </div>
 
             {/* Skills Analysis */}
@@ -316,7 +356,7 @@ export default function DashboardPage() {
 
                 <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-6">
                   {jobs.map((job) => (
-                    <JobCard key={job.id} job={job} />
+                    <JobCard key={job.id} job={job} fallbackMode={fallbackMode} />
                   ))}
                 </div>
               </div>
@@ -239,7 +239,7 @@ export default function Home() {
                       Upload Your Resume
                     </h3>
                     <p className="text-slate-400 leading-relaxed">
-                      Simply drag and drop your resume in PDF format. Add an optional job description for targeted analysis and better matching results.
+                      Simply drag and drop your resume as PDF, DOCX, TXT or Markdown. Add an optional job description for targeted analysis and better matching results.
                     </p>
                   </div>
                 </div>
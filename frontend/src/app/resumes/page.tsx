@@ -357,14 +357,12 @@ export default function ResumesPage() {
                     </div>
 
                     {/* Download Resume */}
-                    <a
-                      href={selectedResume.file_url}
-                      target="_blank"
-                      rel="noopener noreferrer"
+                    <button
+                      onClick={() => resumeAPI.openResumeFile(selectedResume.id).catch((err) => alert(err.message))}
                       className="block w-full px-4 py-3 bg-primary-500 text-white text-center rounded-lg hover:bg-primary-600 transition-colors"
                     >
                       Download Resume
-                    </a>
+                    </button>
                   </div>
                 </div>
               ) : (
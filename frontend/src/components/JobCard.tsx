@@ -1,13 +1,20 @@
 'use client';
 
-import { JobRecommendation } from '@/lib/api';
+import { JobFallbackMode, JobRecommendation } from '@/lib/api';
 import { formatDate, truncateText } from '@/lib/utils';
 
 interface JobCardProps {
   job: JobRecommendation;
+  fallbackMode?: JobFallbackMode; // fallback that generated synthetic jobs
 }
 
-export default function JobCard({ job }: JobCardProps) {
+// Labels of synthetic jobs by the fallback that generated them
+const fallbackLabels: Record<string, string> = {
+  demo: 'Demo',
+  placeholder: 'Placeholder',
+};
+
+export default function JobCard({ job, fallbackMode }: JobCardProps) {
   return (
     <div className="group bg-white dark:bg-slate-800 rounded-xl p-6 border border-slate-200 dark:border-slate-700 hover:shadow-xl hover:border-primary-400 dark:hover:border-primary-600 transition-all duration-300 hover:-translate-y-1">
       <div className="flex items-start justify-between mb-3">
@@ -19,6 +26,11 @@ export default function JobCard({ job }: JobCardProps) {
             {job.company}
           </p>
         </div>
+        {job.synthetic && (
+          <span className="ml-2 px-3 py-1 text-xs font-medium rounded-full bg-amber-100 dark:bg-amber-900/30 text-amber-700 dark:text-amber-300 border border-amber-200 dark:border-amber-800">
+            {fallbackLabels[fallbackMode || ''] || 'Sample'}
+          </span>
+        )}
         {job.job_type && (
           <span className="px-3 py-1 text-xs font-medium rounded-full bg-gradient-to-r from-primary-100 to-secondary-100 dark:from-primary-900/30 dark:to-secondary-900/30 text-primary-700 dark:text-primary-300 border border-primary-200 dark:border-primary-800">
             {job.job_type}
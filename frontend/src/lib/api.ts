@@ -42,6 +42,23 @@ const apiClient = async (
   return response;
 };
 
+// Error with the machine readable code the backend sends next to the message
+export class ApiError extends Error {
+  constructor(message: string, public status: number, public code?: string) {
+    super(message);
+    this.name = 'ApiError';
+  }
+}
+
+// Codes of rejected resume uploads
+export type UploadErrorCode =
+  | 'file_required'
+  | 'file_too_large'
+  | 'empty_file'
+  | 'unsupported_format'
+  | 'unreadable_file'
+  | 'too_many_pages';
+
 // Types
 export interface User {
   id: number;
@@ -79,13 +96,82 @@ export interface Resume {
   id: number;
   user_id: number;
   title: string;
-  file_url: string;
+  file_url: string; // protected endpoint, /api/resume/:id/file
+  file_id?: string;
+  file_format?: 'pdf' | 'docx' | 'txt' | 'md';
+  content_hash?: string;
   analysis_result: string;
   ats_score: number;
   jd_match_score: number;
   matching_skills: string; // JSON string array
   missing_skills: string; // JSON string array
+  sections?: string; // JSON ParsedResume
+  extraction_report?: string; // JSON ExtractionReport
+  status?: ResumeStatus;
+  stage?: string;
+  error?: string;
   uploaded_at: string;
+  updated_at?: string;
+}
+
+export type ResumeStatus = 'pending' | 'processing' | 'done' | 'failed';
+
+export interface ResumeStage {
+  stage: string; // extracting, analyzing, uploading, fetching_jobs
+  status: 'pending' | 'running' | 'done' | 'failed' | 'skipped';
+  error?: string;
+  started_at?: string;
+  finished_at?: string;
+}
+
+export interface ResumeStatusResponse {
+  resume_id: number;
+  status: ResumeStatus;
+  stage: string;
+  stages: ResumeStage[];
+  error: string;
+  ats_warnings: AtsWarning[];
+}
+
+// Signed link to the event stream of a resume, for EventSource (no Authorization header)
+export interface ResumeEventsUrlResponse {
+  url: string; // /api/events/:id?expires=...&sig=...
+  expires_at: string;
+}
+
+export interface UploadAcceptedResponse {
+  message: string;
+  resume_id: number;
+  status: ResumeStatus;
+  status_url: string;
+  duplicate?: boolean; // identical file was uploaded before, its resume is returned
+  ats_warnings?: AtsWarning[]; // only for duplicates, new uploads report them on the status
+}
+
+// ATS readability problem found while extracting the resume text
+export interface AtsWarning {
+  code:
+    | 'image_only_pages'
+    | 'unreadable_pages'
+    | 'pages_without_text'
+    | 'non_printable_text'
+    | 'little_text'
+    | 'text_truncated';
+  message: string;
+  pages?: number[];
+}
+
+// How much of the resume file could be read as text, Resume.extraction_report
+export interface ExtractionReport {
+  format: string;
+  pages_total: number;
+  pages_extracted: number;
+  chars_per_page: number[];
+  non_printable_share: number; // 0-1
+  image_only_pages: number[];
+  failed_pages: number[];
+  truncated: boolean;
+  warnings: AtsWarning[];
 }
 
 export interface JobRecommendation {
@@ -99,11 +185,20 @@ export interface JobRecommendation {
   job_url: string;
   posted_date: string;
   job_type: string;
+  source?: string; // provider the posting came from, e.g. "remoteok"
+  source_id?: string; // provider's own posting ID
+  score?: number; // relevance score (0-100)
+  matched_skills?: string | string[]; // JSON string array when loaded from /jobs, array in upload response
+  synthetic?: boolean; // generated by the fallback, not a real posting
   created_at: string;
 }
 
+// JOB_FALLBACK_MODE that generated synthetic jobs, '' for real postings
+export type JobFallbackMode = 'demo' | 'placeholder' | '';
+
 export interface UploadResumeResponse {
   message: string;
+  resume_id: number;
   file_url: string;
   analysis_result: string;
   ats_score: number;
@@ -111,17 +206,103 @@ export interface UploadResumeResponse {
   matching_skills: string;
   missing_skills: string;
   recommended_jobs: JobRecommendation[];
+  synthetic_jobs?: boolean; // true when recommended_jobs came from the fallback
+  fallback_mode?: JobFallbackMode; // which fallback generated them
+  ats_warnings: AtsWarning[];
 }
 
+// Resume.analysis_result, validated against the analyzer contract by the backend
 export interface AnalysisResult {
+  schema_version: number;
+  analyzer: 'remote' | 'local'; // Python service or built-in Go analyzer
+  entities: { text: string; label: string; start: number; end: number; score: number }[];
   ats_score: number;
   jd_match_score: number;
   skills: string[];
+  summary: string;
   matching_skills: string[];
   missing_skills: string[];
-  experience_years: number;
-  education_level: string;
-  recommendations: string[];
+  contract_warnings?: string[]; // tolerated deviations, e.g. unknown fields
+}
+
+// A saved job description, JobDescription in the backend
+export interface JobDescription {
+  id: number;
+  user_id: number;
+  title: string;
+  company: string;
+  raw_text: string;
+  source_url: string;
+  required_skills: string; // JSON array, use parseSkills
+  preferred_skills: string; // JSON array, use parseSkills
+  created_at: string;
+  updated_at: string;
+}
+
+export interface JobDescriptionInput {
+  title: string;
+  company?: string;
+  raw_text: string;
+  source_url?: string;
+}
+
+// Stored extracted text of a resume
+export interface ResumeTextResponse {
+  resume_id: number;
+  text: string;
+  format: 'pdf' | 'docx' | 'txt' | 'md';
+  extractor_version: string;
+  current: boolean; // false when an older extractor version produced it
+  extracted_at: string;
+}
+
+// One analysis run of a resume, the upload's or a re-analysis against another job description
+export interface ResumeAnalysis {
+  id: number;
+  resume_id: number;
+  job_description: string;
+  job_description_id: number | null; // saved job description it came from
+  analyzer: 'remote' | 'local';
+  analysis_result: string; // JSON AnalysisResult, use parseAnalysisResult
+  ats_score: number;
+  jd_match_score: number;
+  matching_skills: string; // JSON array, use parseSkills
+  missing_skills: string; // JSON array, use parseSkills
+  created_at: string;
+}
+
+// Resume sections parsed by the backend, Resume.sections
+export interface ParsedResume {
+  contact: {
+    name: string;
+    email: string;
+    phone: string;
+    location: string;
+    links: string[];
+  };
+  summary: string;
+  experience: {
+    title: string;
+    employer: string;
+    start_date: string;
+    end_date: string; // "Present" for current positions
+    current: boolean;
+    highlights: string[];
+  }[];
+  education: {
+    institution: string;
+    degree: string;
+    start_date: string;
+    end_date: string;
+    details: string[];
+  }[];
+  skills: string[];
+  projects: {
+    name: string;
+    description: string;
+    technologies: string[];
+  }[];
+  certifications: string[];
 }
 
 // Auth API
@@ -175,7 +356,10 @@ export const resumeAPI = {
   upload: async (
     file: File,
     title: string,
-    jobDescription?: string
+    jobDescription?: string,
+    force = false,
+    jobDescriptionId?: number, // saved job description, in place of jobDescription
+    onStage?: (stage: ResumeStage) => void // processing progress
   ): Promise<UploadResumeResponse> => {
     const formData = new FormData();
     formData.append('resume', file);
@@ -183,20 +367,129 @@ export const resumeAPI = {
     if (jobDescription) {
       formData.append('job_description', jobDescription);
     }
+    if (jobDescriptionId) {
+      formData.append('job_description_id', String(jobDescriptionId));
+    }
+    if (force) {
+      formData.append('force', 'true');
+    }
 
     const response = await apiClient('/api/resume/upload', {
       method: 'POST',
       body: formData,
     });
 
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({}));
+      throw new ApiError(error.error || 'Resume upload failed', response.status, error.code);
+    }
+
+    // Processing runs in the background, wait for it to finish
+    const accepted: UploadAcceptedResponse = await response.json();
+    const status = await resumeAPI.watchProcessing(accepted.resume_id, onStage);
+    if (status.status === 'failed') {
+      throw new Error(status.error || 'Resume processing failed');
+    }
+
+    const { resume } = await resumeAPI.getResumeById(accepted.resume_id);
+    const { jobs, synthetic_jobs, fallback_mode } = await resumeAPI.getResumeJobs(accepted.resume_id);
+
+    return {
+      message: 'Resume uploaded successfully',
+      resume_id: resume.id,
+      file_url: resume.file_url,
+      analysis_result: resume.analysis_result,
+      ats_score: resume.ats_score,
+      jd_match_score: resume.jd_match_score,
+      matching_skills: resume.matching_skills,
+      missing_skills: resume.missing_skills,
+      recommended_jobs: jobs,
+      synthetic_jobs,
+      fallback_mode,
+      ats_warnings: status.ats_warnings || [],
+    };
+  },
+
+  getResumeStatus: async (id: number): Promise<ResumeStatusResponse> => {
+    const response = await apiClient(`/api/resume/${id}/status`);
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to fetch resume status');
+    }
+
+    return response.json();
+  },
+
+  getResumeEventsUrl: async (id: number): Promise<ResumeEventsUrlResponse> => {
+    const response = await apiClient(`/api/resume/${id}/events/url`);
+
     if (!response.ok) {
       const error = await response.json();
-      throw new Error(error.error || 'Resume upload failed');
+      throw new Error(error.error || 'Failed to fetch resume events link');
     }
 
     return response.json();
   },
 
+  // Follows the processing progress over Server-Sent Events until it is done or failed.
+  // Falls back to polling when the stream can't be opened or breaks for good
+  // (e.g. its signed link expired before a reconnect).
+  watchProcessing: async (
+    id: number,
+    onStage?: (stage: ResumeStage) => void
+  ): Promise<ResumeStatusResponse> => {
+    if (typeof EventSource === 'undefined') {
+      return resumeAPI.waitForProcessing(id);
+    }
+
+    let url: string;
+    try {
+      ({ url } = await resumeAPI.getResumeEventsUrl(id));
+    } catch {
+      return resumeAPI.waitForProcessing(id);
+    }
+
+    await new Promise<void>((resolve) => {
+      const source = new EventSource(`${API_BASE_URL}${url}`);
+      const close = () => {
+        source.close();
+        resolve();
+      };
+      source.addEventListener('stage', (e) => onStage?.(JSON.parse((e as MessageEvent).data)));
+      source.addEventListener('done', close);
+      source.addEventListener('failed', close);
+      source.onerror = () => {
+        // EventSource reconnects by itself (with Last-Event-ID) unless the stream is closed
+        if (source.readyState === EventSource.CLOSED) {
+          close();
+        }
+      };
+    });
+
+    // The status has the final error and ATS warnings; keeps polling if the stream broke early
+    return resumeAPI.waitForProcessing(id);
+  },
+
+  // Polls the resume status until processing is done or failed
+  waitForProcessing: async (
+    id: number,
+    intervalMs = 2000,
+    timeoutMs = 5 * 60 * 1000
+  ): Promise<ResumeStatusResponse> => {
+    const deadline = Date.now() + timeoutMs;
+    for (;;) {
+      const status = await resumeAPI.getResumeStatus(id);
+      if (status.status === 'done' || status.status === 'failed') {
+        return status;
+      }
+      if (Date.now() > deadline) {
+        throw new Error('Resume processing is taking too long, check your resumes later');
+      }
+      await new Promise((resolve) => setTimeout(resolve, intervalMs));
+    }
+  },
+
   getUserResumes: async (): Promise<{ resumes: Resume[] }> => {
     const response = await apiClient('/api/resumes');
 
@@ -219,6 +512,21 @@ export const resumeAPI = {
     return response.json();
   },
 
+  // Resume files are private: fetch them with the auth header and open a local blob URL
+  openResumeFile: async (id: number): Promise<void> => {
+    const response = await apiClient(`/api/resume/${id}/file`);
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to download resume');
+    }
+
+    const blob = await response.blob();
+    const url = URL.createObjectURL(blob);
+    window.open(url, '_blank', 'noopener,noreferrer');
+    setTimeout(() => URL.revokeObjectURL(url), 60_000);
+  },
+
   deleteResume: async (id: number): Promise<{ message: string }> => {
     const response = await apiClient(`/api/resume/${id}`, {
       method: 'DELETE',
@@ -232,7 +540,53 @@ export const resumeAPI = {
     return response.json();
   },
 
-  getResumeJobs: async (id: number): Promise<{ jobs: JobRecommendation[] }> => {
+  getResumeText: async (id: number): Promise<ResumeTextResponse> => {
+    const response = await apiClient(`/api/resume/${id}/text`);
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to fetch resume text');
+    }
+
+    return response.json();
+  },
+
+  // Analyze an already processed resume against another job description, raw text or a saved one's id
+  analyzeResume: async (
+    id: number,
+    jobDescription: string | { jobDescriptionId: number }
+  ): Promise<{ analysis: ResumeAnalysis }> => {
+    const body =
+      typeof jobDescription === 'string'
+        ? { job_description: jobDescription }
+        : { job_description_id: jobDescription.jobDescriptionId };
+    const response = await apiClient(`/api/resume/${id}/analyze`, {
+      method: 'POST',
+      body: JSON.stringify(body),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new ApiError(error.error || 'Failed to analyze resume', response.status, error.kind);
+    }
+
+    return response.json();
+  },
+
+  getResumeAnalyses: async (id: number): Promise<{ analyses: ResumeAnalysis[] }> => {
+    const response = await apiClient(`/api/resume/${id}/analyses`);
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to fetch analyses');
+    }
+
+    return response.json();
+  },
+
+  getResumeJobs: async (
+    id: number
+  ): Promise<{ jobs: JobRecommendation[]; synthetic_jobs?: boolean; fallback_mode?: JobFallbackMode }> => {
     const response = await apiClient(`/api/resume/${id}/jobs`);
 
     if (!response.ok) {
@@ -244,6 +598,72 @@ export const resumeAPI = {
   },
 };
 
+// Saved job descriptions API
+export const jobDescriptionAPI = {
+  list: async (): Promise<{ job_descriptions: JobDescription[] }> => {
+    const response = await apiClient('/api/job-descriptions');
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to fetch job descriptions');
+    }
+
+    return response.json();
+  },
+
+  get: async (id: number): Promise<{ job_description: JobDescription }> => {
+    const response = await apiClient(`/api/job-descriptions/${id}`);
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to fetch job description');
+    }
+
+    return response.json();
+  },
+
+  create: async (data: JobDescriptionInput): Promise<{ job_description: JobDescription }> => {
+    const response = await apiClient('/api/job-descriptions', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to save job description');
+    }
+
+    return response.json();
+  },
+
+  update: async (id: number, data: JobDescriptionInput): Promise<{ job_description: JobDescription }> => {
+    const response = await apiClient(`/api/job-descriptions/${id}`, {
+      method: 'PUT',
+      body: JSON.stringify(data),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to save job description');
+    }
+
+    return response.json();
+  },
+
+  delete: async (id: number): Promise<{ message: string }> => {
+    const response = await apiClient(`/api/job-descriptions/${id}`, {
+      method: 'DELETE',
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to delete job description');
+    }
+
+    return response.json();
+  },
+};
+
 // Helper function to parse JSON strings from backend
 export const parseSkills = (skillsJson: string): string[] => {
   try {
@@ -261,3 +681,25 @@ export const parseAnalysisResult = (analysisJson: string): AnalysisResult | null
     return null;
   }
 };
+
+// Helper function to parse the extraction report
+export const parseExtractionReport = (reportJson?: string): ExtractionReport | null => {
+  if (!reportJson) return null;
+  try {
+    const report = JSON.parse(reportJson);
+    return report && report.format ? report : null;
+  } catch (e) {
+    return null;
+  }
+};
+
+// Helper function to parse resume sections
+export const parseSections = (sectionsJson?: string): ParsedResume | null => {
+  if (!sectionsJson) return null;
+  try {
+    const sections = JSON.parse(sectionsJson);
+    return sections && sections.contact ? sections : null;
+  } catch (e) {
+    return null;
+  }
+};